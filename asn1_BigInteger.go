@@ -0,0 +1,278 @@
+package asn1_per
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// INTEGER Type Сonstrained with arbitrary-precision bounds and value
+
+type BigConstrainedInteger struct {
+	LowerBand *big.Int
+	UpperBand *big.Int
+	Alligned  bool
+	Value     *big.Int
+}
+
+func NewBigConstrainedInteger(lb *big.Int, ub *big.Int, alligned bool) *BigConstrainedInteger {
+	return &BigConstrainedInteger{
+		LowerBand: lb,
+		UpperBand: ub,
+		Alligned:  alligned,
+		Value:     new(big.Int).Set(lb),
+	}
+}
+
+func (c *BigConstrainedInteger) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	var rang *big.Int
+	if rang, err = c.rang(); err != nil {
+		return
+	}
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	offset := new(big.Int)
+	// ranges up to 64 bits (uint64 fast path)
+	if span := new(big.Int).Sub(rang, big.NewInt(1)); span.IsUint64() {
+		var value uint64
+		value, outData, outShift, err = constrainedWholeNumber64(data, shift, span.Uint64(), c.Alligned)
+		if err != nil {
+			return
+		}
+		offset.SetUint64(value)
+	} else {
+		offset, outData, outShift, err = bigConstrainedWholeNumber(data, shift, rang, c.Alligned)
+		if err != nil {
+			return
+		}
+	}
+	if offset.Cmp(rang) >= 0 {
+		err = ErrorConstraintViolation
+		return
+	}
+	c.Value = offset.Add(offset, c.LowerBand)
+	return
+}
+
+func (c *BigConstrainedInteger) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	var rang *big.Int
+	if rang, err = c.rang(); err != nil {
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if c.Value == nil {
+		err = ErrorInputParameters
+		return
+	}
+	if c.Value.Cmp(c.LowerBand) < 0 || c.Value.Cmp(c.UpperBand) > 0 {
+		err = ErrorConstraintViolation
+		return
+	}
+	offset := new(big.Int).Sub(c.Value, c.LowerBand)
+	// ranges up to 64 bits (uint64 fast path)
+	if span := new(big.Int).Sub(rang, big.NewInt(1)); span.IsUint64() {
+		return encodeConstrainedWholeNumber64(data, shift, offset.Uint64(), span.Uint64(), c.Alligned)
+	}
+	outData, outShift = encodeBigConstrainedWholeNumber(data, shift, offset, rang, c.Alligned)
+	return
+}
+
+// Decode from current position of BitReader
+func (c *BigConstrainedInteger) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *BigConstrainedInteger) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
+}
+
+// range ub - lb + 1
+func (c *BigConstrainedInteger) rang() (rang *big.Int, err error) {
+	if c.LowerBand == nil || c.UpperBand == nil || c.LowerBand.Cmp(c.UpperBand) >= 0 {
+		err = ErrorInputParameters
+		return
+	}
+	rang = new(big.Int).Sub(c.UpperBand, c.LowerBand)
+	return rang.Add(rang, big.NewInt(1)), nil
+}
+
+// INTEGER Type Semi-constrained (lb..MAX) with arbitrary-precision value
+
+type BigSemiConstrainedInteger struct {
+	LowerBand *big.Int
+	Alligned  bool
+	Value     *big.Int
+}
+
+func NewBigSemiConstrainedInteger(lb *big.Int, alligned bool) *BigSemiConstrainedInteger {
+	return &BigSemiConstrainedInteger{
+		LowerBand: lb,
+		Alligned:  alligned,
+		Value:     new(big.Int).Set(lb),
+	}
+}
+
+func (c *BigSemiConstrainedInteger) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if c.LowerBand == nil {
+		err = ErrorInputParameters
+		return
+	}
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	var octets []byte
+	octets, outData, outShift, err = lengthBigOctetsDecode(data, shift, c.Alligned)
+	if err != nil {
+		return
+	}
+	offset := new(big.Int).SetBytes(octets)
+	c.Value = offset.Add(offset, c.LowerBand)
+	return
+}
+
+func (c *BigSemiConstrainedInteger) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if c.LowerBand == nil || c.Value == nil {
+		err = ErrorInputParameters
+		return
+	}
+	if c.Value.Cmp(c.LowerBand) < 0 {
+		err = ErrorConstraintViolation
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	octets := new(big.Int).Sub(c.Value, c.LowerBand).Bytes()
+	if len(octets) == 0 {
+		octets = []byte{0x00}
+	}
+	return openTypeEncode(data, shift, octets, c.Alligned)
+}
+
+// Decode from current position of BitReader
+func (c *BigSemiConstrainedInteger) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *BigSemiConstrainedInteger) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
+}
+
+// INTEGER Type Unconstrained with arbitrary-precision value
+
+type BigUnconstrainedInteger struct {
+	Alligned bool
+	Value    *big.Int
+}
+
+func NewBigUnconstrainedInteger(alligned bool) *BigUnconstrainedInteger {
+	return &BigUnconstrainedInteger{
+		Alligned: alligned,
+		Value:    new(big.Int),
+	}
+}
+
+func (c *BigUnconstrainedInteger) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	var octets []byte
+	octets, outData, outShift, err = lengthBigOctetsDecode(data, shift, c.Alligned)
+	if err != nil {
+		return
+	}
+	c.Value = parseBigInt(octets)
+	return
+}
+
+func (c *BigUnconstrainedInteger) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if c.Value == nil {
+		err = ErrorInputParameters
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	return openTypeEncode(data, shift, bigIntOctets(c.Value), c.Alligned)
+}
+
+// Decode from current position of BitReader
+func (c *BigUnconstrainedInteger) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *BigUnconstrainedInteger) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
+}
+
+// Constrained whole number for range > 64K: bit-field in unaligned variant,
+// in aligned variant length (1..number of octets for range) and octet-aligned value
+func bigConstrainedWholeNumber(data []byte, shift uint8, rang *big.Int, aligned bool) (result *big.Int, outData []byte, outShift uint8, err error) {
+	bitSize := new(big.Int).Sub(rang, big.NewInt(1)).BitLen()
+	var value []byte
+	if !aligned {
+		outData, outShift, err = fixedBitStringDecode(data, shift, bitSize, false, &value)
+		result = new(big.Int).SetBytes(value)
+		return
+	}
+	var length uint64
+	length, data, shift, err = getBits(data, shift, bits.Len(uint(byteSizeCalculate(bitSize)-1)))
+	if err != nil {
+		return
+	}
+	if shift != 0 {
+		data = data[1:]
+	}
+	outData, outShift, err = fixedOctetStringDecode(data, 0, int(length)+1, false, &value)
+	result = new(big.Int).SetBytes(value)
+	return
+}
+
+func encodeBigConstrainedWholeNumber(data []byte, shift uint8, value *big.Int, rang *big.Int, aligned bool) (outData []byte, outShift uint8) {
+	bitSize := new(big.Int).Sub(rang, big.NewInt(1)).BitLen()
+	if !aligned {
+		return putBitString(data, shift, value.FillBytes(make([]byte, byteSizeCalculate(bitSize))), bitSize)
+	}
+	octets := value.Bytes()
+	if len(octets) == 0 {
+		octets = []byte{0x00}
+	}
+	data, _ = putBits(data, shift, uint64(len(octets)-1), bits.Len(uint(byteSizeCalculate(bitSize)-1)))
+	return putBitString(data, 0, octets, len(octets)*8)
+}
+
+// 2's-complement-binary-integer in minimum number of octets
+func bigIntOctets(value *big.Int) []byte {
+	if value.Sign() >= 0 {
+		octets := value.Bytes()
+		if len(octets) == 0 || octets[0]&0x80 != 0 {
+			octets = append([]byte{0x00}, octets...)
+		}
+		return octets
+	}
+	// -value - 1 has the same bits as value with inverted octets
+	octets := new(big.Int).Not(value).Bytes()
+	if len(octets) == 0 || octets[0]&0x80 != 0 {
+		octets = append([]byte{0x00}, octets...)
+	}
+	for i := range octets {
+		octets[i] = ^octets[i]
+	}
+	return octets
+}
+
+func parseBigInt(in []byte) *big.Int {
+	value := new(big.Int).SetBytes(in)
+	if len(in) > 0 && in[0]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(len(in))*8))
+	}
+	return value
+}
@@ -0,0 +1,171 @@
+package asn1_per
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func bigInt(s string) *big.Int {
+	value, _ := new(big.Int).SetString(s, 0)
+	return value
+}
+
+func TestBigInteger(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+	}
+	for _, test := range []struct {
+		name    string
+		integer Codec
+		value   *big.Int
+		want    result
+	}{
+		{
+			name:    `Constrained_Max_Uint64`,
+			integer: NewBigConstrainedInteger(big.NewInt(0), bigInt("18446744073709551615"), true),
+			value:   bigInt("18446744073709551615"),
+			want:    result{data: []byte{0xe0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		},
+		{
+			name:    `Constrained_One_Octet`,
+			integer: NewBigConstrainedInteger(big.NewInt(0), bigInt("18446744073709551615"), true),
+			value:   big.NewInt(1),
+			want:    result{data: []byte{0x00, 0x01}},
+		},
+		{
+			name:    `Constrained_Unaligned`,
+			integer: NewBigConstrainedInteger(big.NewInt(0), bigInt("18446744073709551615"), false),
+			value:   big.NewInt(5),
+			want:    result{data: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05}},
+		},
+		{
+			name:    `Constrained_Fast_Path`,
+			integer: NewBigConstrainedInteger(big.NewInt(-10), big.NewInt(10), true),
+			value:   big.NewInt(3),
+			want:    result{data: []byte{0x68}, shift: 5},
+		},
+		{
+			name:    `Constrained_Beyond_Int64`,
+			integer: NewBigConstrainedInteger(bigInt("-0x1000000000000000000"), bigInt("0x1000000000000000000"), true),
+			value:   bigInt("0x1000000000000000000"),
+			want:    result{data: []byte{0x90, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    `Semi_Constrained`,
+			integer: NewBigSemiConstrainedInteger(big.NewInt(0), true),
+			value:   bigInt("0x400000000000000000"),
+			want:    result{data: []byte{0x09, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    `Unconstrained_Negative`,
+			integer: NewBigUnconstrainedInteger(true),
+			value:   bigInt("-0x400000000000000000"),
+			want:    result{data: []byte{0x09, 0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    `Unconstrained_Sign_Octet`,
+			integer: NewBigUnconstrainedInteger(true),
+			value:   big.NewInt(128),
+			want:    result{data: []byte{0x02, 0x00, 0x80}},
+		},
+		{
+			name:    `Unconstrained_Minus_129`,
+			integer: NewBigUnconstrainedInteger(false),
+			value:   big.NewInt(-129),
+			want:    result{data: []byte{0x02, 0xff, 0x7f}},
+		},
+		{
+			name:    `Unconstrained_Minus_128`,
+			integer: NewBigUnconstrainedInteger(false),
+			value:   big.NewInt(-128),
+			want:    result{data: []byte{0x01, 0x80}},
+		},
+	} {
+		value := func() **big.Int {
+			switch v := test.integer.(type) {
+			case *BigConstrainedInteger:
+				return &v.Value
+			case *BigSemiConstrainedInteger:
+				return &v.Value
+			case *BigUnconstrainedInteger:
+				return &v.Value
+			}
+			return nil
+		}()
+		*value = test.value
+		res := result{}
+		var err error
+		res.data, res.shift, err = test.integer.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode integer %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s encoding is not expected \n want %x, \n got  %x", test.name, test.want, res)
+			t.Fail()
+		}
+		*value = nil
+		out, shift, err := test.integer.Decode(append(res.data, 0xab), 0)
+		if err != nil {
+			t.Fatalf("%s error decode integer %v", test.name, err)
+		}
+		if (*value).Cmp(test.value) != 0 || shift != res.shift || !bytes.HasSuffix(out, []byte{0xab}) {
+			t.Errorf("%s result is not expected \n want %v, \n got  %v", test.name, test.value, *value)
+		}
+	}
+}
+
+// values outside of constraint return ErrorConstraintViolation
+func TestBigIntegerConstraintViolation(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		integer Codec
+		value   *big.Int
+		input   []byte // decode if not nil
+	}{
+		{
+			name:    `Encode_Below_Lower_Band`,
+			integer: NewBigConstrainedInteger(big.NewInt(1), big.NewInt(15), true),
+			value:   big.NewInt(0),
+		},
+		{
+			name:    `Encode_Above_Upper_Band`,
+			integer: NewBigConstrainedInteger(big.NewInt(0), bigInt("0x10000000000000000"), true),
+			value:   bigInt("0x10000000000000001"),
+		},
+		{
+			name:    `Decode_Above_Range`,
+			integer: NewBigConstrainedInteger(big.NewInt(0), big.NewInt(2), false),
+			input:   []byte{0xc0},
+		},
+		{
+			name:    `Decode_Above_Big_Range`,
+			integer: NewBigConstrainedInteger(big.NewInt(0), bigInt("0x10000000000000000"), true),
+			input:   []byte{0x80, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:    `Semi_Encode_Below_Lower_Band`,
+			integer: NewBigSemiConstrainedInteger(big.NewInt(-5), true),
+			value:   big.NewInt(-6),
+		},
+	} {
+		var err error
+		if test.input != nil {
+			_, _, err = test.integer.Decode(test.input, 0)
+		} else {
+			switch c := test.integer.(type) {
+			case *BigConstrainedInteger:
+				c.Value = test.value
+			case *BigSemiConstrainedInteger:
+				c.Value = test.value
+			}
+			_, _, err = test.integer.Encode(nil, 0)
+		}
+		if err != ErrorConstraintViolation {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, ErrorConstraintViolation, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package asn1_per
+
+// Bit-level reader, track absolute bit position (from the begin of data)
+
+type BitReader struct {
+	data     []byte
+	position int
+}
+
+func NewBitReader(data []byte) *BitReader {
+	return &BitReader{
+		data:     data,
+		position: 0,
+	}
+}
+
+// Read n bits (n <= 64), return them as non-negative-binary-integer
+func (r *BitReader) ReadBits(n int) (value uint64, err error) {
+	value, err = r.PeekBits(n)
+	if err != nil {
+		return
+	}
+	r.position += n
+	return
+}
+
+// Return next n bits (n <= 64) without moving of position
+func (r *BitReader) PeekBits(n int) (value uint64, err error) {
+	if n < 0 || n > 64 {
+		err = ErrorInputParameters
+		return
+	}
+	if n > r.Remaining() {
+		err = ErrorBufferToShort
+		return
+	}
+	for i := r.position; i < r.position+n; i++ {
+		value = value<<1 | uint64((r.data[i/8]>>(7-i%8))&1)
+	}
+	return
+}
+
+// Skip padding bits up to the begin of next octet
+func (r *BitReader) AlignToOctet() {
+	r.position = (r.position + 7) / 8 * 8
+}
+
+// Number of not readed bits
+func (r *BitReader) Remaining() int {
+	return len(r.data)*8 - r.position
+}
+
+// Number of readed bits
+func (r *BitReader) Position() int {
+	return r.position
+}
+
+// Call Decode function of type with (data, shift) of current position and move position
+func (r *BitReader) decode(decode func(data []byte, shift uint8) ([]byte, uint8, error)) error {
+	outData, outShift, err := decode(r.data[r.position/8:], uint8(r.position%8))
+	if err != nil {
+		return err
+	}
+	r.position = (len(r.data)-len(outData))*8 + int(outShift)
+	return nil
+}
+
+// Bit-level writer, track absolute bit position (number of written bits)
+
+type BitWriter struct {
+	data  []byte
+	shift uint8 // number of used bits in the last octet
+}
+
+func NewBitWriter() *BitWriter {
+	return &BitWriter{}
+}
+
+// Write n low bits of value (n <= 64)
+func (w *BitWriter) WriteBits(value uint64, n int) error {
+	if n < 0 || n > 64 {
+		return ErrorInputParameters
+	}
+	w.data, w.shift = putBits(w.data, w.shift, value, n)
+	return nil
+}
+
+// Add zero padding bits up to the begin of next octet
+func (w *BitWriter) AlignToOctet() {
+	w.shift = 0
+}
+
+// Number of written bits
+func (w *BitWriter) Position() int {
+	if w.shift == 0 {
+		return len(w.data) * 8
+	}
+	return (len(w.data)-1)*8 + int(w.shift)
+}
+
+// Encoded data (last octet is padded by zero bits)
+func (w *BitWriter) Bytes() []byte {
+	return w.data
+}
+
+// Call Encode function of type with (data, shift) of current position and move position
+func (w *BitWriter) encode(encode func(data []byte, shift uint8) ([]byte, uint8, error)) error {
+	data, shift, err := encode(w.data, w.shift)
+	if err != nil {
+		return err
+	}
+	w.data, w.shift = data, shift
+	return nil
+}
@@ -0,0 +1,111 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBitReader(t *testing.T) {
+	r := NewBitReader([]byte{0b1011_0010, 0b0110_1111, 0xff})
+	type result struct {
+		value     uint64
+		position  int
+		remaining int
+	}
+	for _, test := range []struct {
+		name string
+		step func() (uint64, error)
+		want result
+	}{
+		{
+			name: `Read_3`,
+			step: func() (uint64, error) { return r.ReadBits(3) },
+			want: result{value: 0b101, position: 3, remaining: 21},
+		},
+		{
+			name: `Peek_7`,
+			step: func() (uint64, error) { return r.PeekBits(7) },
+			want: result{value: 0b1_0010_01, position: 3, remaining: 21},
+		},
+		{
+			name: `Read_7`,
+			step: func() (uint64, error) { return r.ReadBits(7) },
+			want: result{value: 0b1_0010_01, position: 10, remaining: 14},
+		},
+		{
+			name: `Align`,
+			step: func() (uint64, error) { r.AlignToOctet(); return 0, nil },
+			want: result{value: 0, position: 16, remaining: 8},
+		},
+		{
+			name: `Read_8`,
+			step: func() (uint64, error) { return r.ReadBits(8) },
+			want: result{value: 0xff, position: 24, remaining: 0},
+		},
+	} {
+		res := result{}
+		var err error
+		res.value, err = test.step()
+		if err != nil {
+			t.Errorf("%s error %v", test.name, err)
+		}
+		res.position = r.Position()
+		res.remaining = r.Remaining()
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+	}
+	if _, err := r.ReadBits(1); err != ErrorBufferToShort {
+		t.Errorf("want error %v, got %v", ErrorBufferToShort, err)
+	}
+}
+
+// SEQUENCE{ BIT STRING (SIZE (4)), INTEGER(0..15), OCTET STRING (SIZE (2)) }
+func TestBitStreamSequence(t *testing.T) {
+	data := []byte{0xa5, 0x00, 0x07}
+
+	r := NewBitReader(data)
+	bit := NewFixedBitString(4, true)
+	integer := NewConstrainedInteger(0, 15, true)
+	octet := NewFixedOctetString(2, true)
+	for _, decode := range []func(*BitReader) error{bit.DecodeFrom, integer.DecodeFrom, octet.DecodeFrom} {
+		if err := decode(r); err != nil {
+			t.Fatalf("error decode %v", err)
+		}
+	}
+	if !reflect.DeepEqual(bit.Value, []byte{0x0a}) || integer.Value != 5 || !reflect.DeepEqual(octet.Value, []byte{0x00, 0x07}) {
+		t.Errorf("result is not expected: %v %v %v", bit.Value, integer.Value, octet.Value)
+	}
+	if r.Remaining() != 0 || r.Position() != 24 {
+		t.Errorf("position is not expected: %d (remaining %d)", r.Position(), r.Remaining())
+	}
+
+	w := NewBitWriter()
+	for _, encode := range []func(*BitWriter) error{bit.EncodeTo, integer.EncodeTo, octet.EncodeTo} {
+		if err := encode(w); err != nil {
+			t.Fatalf("error encode %v", err)
+		}
+	}
+	if !reflect.DeepEqual(w.Bytes(), data) || w.Position() != 24 {
+		t.Errorf("result is not expected \n want %v, \n got  %v", data, w.Bytes())
+	}
+}
+
+func TestBitWriter(t *testing.T) {
+	w := NewBitWriter()
+	if err := w.WriteBits(0b101, 3); err != nil {
+		t.Fatalf("error write %v", err)
+	}
+	if w.Position() != 3 {
+		t.Errorf("want position 3, got %d", w.Position())
+	}
+	w.AlignToOctet()
+	if err := w.WriteBits(0x1ff, 9); err != nil {
+		t.Fatalf("error write %v", err)
+	}
+	want := []byte{0b1010_0000, 0xff, 0x80}
+	if !reflect.DeepEqual(w.Bytes(), want) || w.Position() != 17 {
+		t.Errorf("result is not expected \n want %v, \n got  %v (position %d)", want, w.Bytes(), w.Position())
+	}
+}
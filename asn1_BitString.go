@@ -3,9 +3,11 @@ package asn1_per
 // BIT STRING with fixed length
 
 type FixedBitString struct {
-	Size     int
-	Alligned bool
-	Value    []uint8
+	Size       int
+	Alligned   bool
+	Extensible bool // SIZE (size, ...), Value of other size is encoded with length determinant
+	BitLength  int  // Result Size in Bits (Extensible only), Value is outside of root if BitLength != Size
+	Value      []uint8
 }
 
 func NewFixedBitString(size int, alligned bool) *FixedBitString {
@@ -14,12 +16,20 @@ func NewFixedBitString(size int, alligned bool) *FixedBitString {
 		resSize += 1
 	}
 	return &FixedBitString{
-		Size:     size,
-		Alligned: alligned,
-		Value:    make([]uint8, 0, resSize),
+		Size:      size,
+		Alligned:  alligned,
+		BitLength: size,
+		Value:     make([]uint8, 0, resSize),
 	}
 }
 
+// BIT STRING with extensible fixed length SIZE (size, ...)
+func NewExtensibleFixedBitString(size int, alligned bool) *FixedBitString {
+	b := NewFixedBitString(size, alligned)
+	b.Extensible = true
+	return b
+}
+
 func (b *FixedBitString) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
 	if len(data) == 0 {
 		err = ErrorBufferToShort
@@ -28,17 +38,70 @@ func (b *FixedBitString) Decode(data []byte, shift uint8) (outData []byte, outSh
 	if len(b.Value) != 0 {
 		b.Value = b.Value[:0]
 	}
+	if b.Extensible {
+		var extended uint64
+		extended, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+		// size outside of root
+		if extended == 1 {
+			b.BitLength, outData, outShift, err = bitStringWithLengthDecode(data, shift, b.Alligned, &b.Value)
+			return
+		}
+	}
+	b.BitLength = b.Size
+	// 64K bits and more, length determinant is encoded (X.691 p.16.11)
+	if b.Size >= 65536 {
+		var size int
+		size, outData, outShift, err = bitStringWithLengthDecode(data, shift, b.Alligned, &b.Value)
+		if err == nil && size != b.Size {
+			err = ErrorIncorrectLength
+		}
+		return
+	}
 	return fixedBitStringDecode(data, shift, b.Size, b.Alligned, &b.Value)
 }
 
+// Encode Value (Value length must be Size bits alligned by the end of octet,
+// or BitLength bits if Extensible)
+func (b *FixedBitString) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if b.Extensible {
+		outside := b.BitLength != b.Size
+		data, shift = putBits(data, shift, boolToBit(outside), 1)
+		// size outside of root
+		if outside {
+			return bitStringWithLengthEncode(data, shift, b.BitLength, b.Alligned, b.Value)
+		}
+	}
+	if b.Size >= 65536 {
+		return bitStringWithLengthEncode(data, shift, b.Size, b.Alligned, b.Value)
+	}
+	return fixedBitStringEncode(data, shift, b.Size, b.Alligned, b.Value)
+}
+
+// Decode from current position of BitReader
+func (b *FixedBitString) DecodeFrom(r *BitReader) error {
+	return r.decode(b.Decode)
+}
+
+// Encode to current position of BitWriter
+func (b *FixedBitString) EncodeTo(w *BitWriter) error {
+	return w.encode(b.Encode)
+}
+
 // BIT STRING with constrained length
 
 type ConstrainedBitString struct {
-	LowerBand int
-	UpperBand int
-	Alligned  bool
-	Size      int // Result Size in Bits
-	Value     []uint8
+	LowerBand  int
+	UpperBand  int
+	Alligned   bool
+	Extensible bool // SIZE (lb..ub, ...), Value of size outside of root is encoded with length determinant
+	Size       int  // Result Size in Bits
+	Value      []uint8
 }
 
 func NewConstrainedBitString(lb int, ub int, alligned bool) *ConstrainedBitString {
@@ -57,6 +120,13 @@ func NewConstrainedBitString(lb int, ub int, alligned bool) *ConstrainedBitStrin
 	}
 }
 
+// BIT STRING with extensible constrained length SIZE (lb..ub, ...)
+func NewExtensibleConstrainedBitString(lb int, ub int, alligned bool) *ConstrainedBitString {
+	b := NewConstrainedBitString(lb, ub, alligned)
+	b.Extensible = true
+	return b
+}
+
 func (b *ConstrainedBitString) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
 	if b.UpperBand < b.LowerBand {
 		err = ErrorInputParameters
@@ -69,19 +139,89 @@ func (b *ConstrainedBitString) Decode(data []byte, shift uint8) (outData []byte,
 	if len(b.Value) != 0 {
 		b.Value = b.Value[:0]
 	}
+	if b.Extensible {
+		var extended uint64
+		extended, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+		// size outside of root
+		if extended == 1 {
+			b.Size, outData, outShift, err = bitStringWithLengthDecode(data, shift, b.Alligned, &b.Value)
+			return
+		}
+	}
+	// ub 64K and more, length determinant is encoded (X.691 p.16.11)
+	if b.UpperBand >= 65536 {
+		b.Size, outData, outShift, err = bitStringWithLengthDecode(data, shift, b.Alligned, &b.Value)
+		if err == nil && (b.Size < b.LowerBand || b.Size > b.UpperBand) {
+			err = ErrorIncorrectLength
+		}
+		return
+	}
 	if b.UpperBand == b.LowerBand {
+		b.Size = b.UpperBand
 		return fixedBitStringDecode(data, shift, b.UpperBand, b.Alligned, &b.Value)
 	}
-	rang := b.UpperBand - b.LowerBand + 1
-
-	b.Size, data, shift, err = constrainedWholeNumber(data, shift, rang, b.Alligned)
+	b.Size, data, shift, err = DecodeConstrainedLength(data, shift, b.LowerBand, b.UpperBand, b.Alligned)
 	if err != nil {
 		return
 	}
-	b.Size += b.LowerBand
 	return fixedBitStringDecode(data, shift, b.Size, b.Alligned, &b.Value)
 }
 
+// Encode Value, Size - size of Value in bits
+func (b *ConstrainedBitString) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if b.UpperBand < b.LowerBand {
+		err = ErrorInputParameters
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if b.Extensible {
+		outside := b.Size < b.LowerBand || b.Size > b.UpperBand
+		data, shift = putBits(data, shift, boolToBit(outside), 1)
+		// size outside of root
+		if outside {
+			return bitStringWithLengthEncode(data, shift, b.Size, b.Alligned, b.Value)
+		}
+	}
+	if b.UpperBand >= 65536 {
+		if b.Size < b.LowerBand || b.Size > b.UpperBand {
+			err = ErrorIncorrectLength
+			return
+		}
+		return bitStringWithLengthEncode(data, shift, b.Size, b.Alligned, b.Value)
+	}
+	if b.UpperBand == b.LowerBand {
+		return fixedBitStringEncode(data, shift, b.UpperBand, b.Alligned, b.Value)
+	}
+	if b.Size < b.LowerBand || b.Size > b.UpperBand {
+		err = ErrorIncorrectLength
+		return
+	}
+	if len(b.Value) != byteSizeCalculate(b.Size) {
+		err = ErrorIncorrectLength
+		return
+	}
+	data, shift, err = EncodeConstrainedLength(data, shift, b.Size, b.LowerBand, b.UpperBand, b.Alligned)
+	if err != nil {
+		return
+	}
+	return fixedBitStringEncode(data, shift, b.Size, b.Alligned, b.Value)
+}
+
+// Decode from current position of BitReader
+func (b *ConstrainedBitString) DecodeFrom(r *BitReader) error {
+	return r.decode(b.Decode)
+}
+
+// Encode to current position of BitWriter
+func (b *ConstrainedBitString) EncodeTo(w *BitWriter) error {
+	return w.encode(b.Encode)
+}
+
 // BIT STRING with unconstrained length
 
 type UnconstrainedBitString struct {
@@ -97,76 +237,92 @@ func NewUnconstrainedBitString(alligned bool) *UnconstrainedBitString {
 }
 
 func (b *UnconstrainedBitString) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
-	if b.Alligned {
-		if shift != 0 {
-			data = data[1:]
-			return b.unconstrainedBitStringAlign(data)
-		}
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
 	}
+	b.Size, outData, outShift, err = bitStringWithLengthDecode(data, shift, b.Alligned, &b.Value)
 	return
 }
 
-// aligned variant
-func (b *UnconstrainedBitString) unconstrainedBitStringAlign(data []byte) (outData []byte, outShift uint8, err error) {
-	var padding int
-	b.Size, err = lengthDeterminanteCalculate(data, true)
-	if err != nil {
-		return
-	}
-	byteLength := b.Size / 8
-	// padding bits for allign to octet
-	padding = 8 - b.Size%8
-	if padding == 8 {
-		padding = 0
-	}
-	if padding != 0 {
-		byteLength += 1
-	}
-	if byteLength > len(data) {
-		err = ErrorIncorrectDecode
+// Encode Value, Size - size of Value in bits (16K bits and more are encoded in fragments)
+func (b *UnconstrainedBitString) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
 		return
 	}
-	b.Value = make([]byte, byteLength)
-
-	// 1 octet length
-	if b.Size < 128 {
-		data = data[1:]
-		return b.nonFragmentationCase(data, byteLength, uint8(padding))
-	}
-
-	// 2 octet length
+	return bitStringWithLengthEncode(data, shift, b.Size, b.Alligned, b.Value)
+}
 
-	if b.Size > 127 && b.Size < 16384 {
-		data = data[2:]
-		return b.nonFragmentationCase(data, byteLength, uint8(padding))
-	}
+// Decode from current position of BitReader
+func (b *UnconstrainedBitString) DecodeFrom(r *BitReader) error {
+	return r.decode(b.Decode)
+}
 
-	// fragmentation case
-	return
+// Encode to current position of BitWriter
+func (b *UnconstrainedBitString) EncodeTo(w *BitWriter) error {
+	return w.encode(b.Encode)
 }
 
-func (b *UnconstrainedBitString) nonFragmentationCase(data []byte, length int, padding uint8) (outData []byte, outShift uint8, err error) {
-	if length > len(data) {
-		err = ErrorIncorrectDecode
-		return
+// Decode BitString with length determinant, fragments of 16K, 32K, 48K or 64K bits
+// are followed by next length determinant (X.691 p.11.9.3.8). Return size in bits
+func bitStringWithLengthDecode(data []byte, shift uint8, alligned bool, value *[]byte) (size int, outData []byte, outShift uint8, err error) {
+	var (
+		length    int
+		fragments [][]byte
+		sizes     []int
+	)
+	for {
+		length, data, shift, err = lengthDeterminantDecode(data, shift, alligned)
+		if err != nil {
+			return
+		}
+		if length != 0 {
+			var fragment []byte
+			data, shift, err = fixedBitStringDecode(data, shift, length, false, &fragment)
+			if err != nil {
+				return
+			}
+			fragments = append(fragments, fragment)
+			sizes = append(sizes, length)
+			size += length
+		}
+		if length < K16 {
+			break
+		}
 	}
-	if padding != 0 {
-		b.allign(data, uint8(padding))
-		outData = data[length-1:]
-		outShift = 8 - uint8(padding)
-	} else {
-		copy(b.Value, data[:length])
-		outData = data[length:]
-		outShift = 0
+	// reassemble fragments, value is alligned by the end of octet
+	var position uint8
+	result := (*value)[:0]
+	if size%8 != 0 {
+		result = append(result, 0x00)
+		position = uint8(8 - size%8)
 	}
-	return
+	for i, fragment := range fragments {
+		result, position = putBitString(result, position, fragment, sizes[i])
+	}
+	if result == nil {
+		result = []byte{}
+	}
+	*value = result
+	return size, data, shift, nil
 }
 
-func (b *UnconstrainedBitString) allign(data []byte, shift uint8) {
-	b.Value[0] = data[0] >> shift
-	for i := 1; i < len(b.Value); i++ {
-		b.Value[i] = (data[i-1] << (8 - shift)) | data[i]>>shift
+// Encode BitString with length determinant, 16K bits and more are encoded in fragments
+func bitStringWithLengthEncode(data []byte, shift uint8, size int, alligned bool, value []byte) (outData []byte, outShift uint8, err error) {
+	if size < 0 || len(value) != byteSizeCalculate(size) {
+		err = ErrorIncorrectLength
+		return
 	}
+	first := len(value)*8 - size
+	for _, length := range lengthFragments(size) {
+		data, shift, err = lengthDeterminantEncode(data, shift, length, alligned)
+		if err != nil {
+			return
+		}
+		data, shift = putBitRange(data, shift, value, first, length)
+		first += length
+	}
+	return data, shift, nil
 }
 
 // Декодирует BitString фиксированной длины, возвращает остаток данных и битовый сдвиг, для дальнейшего декодирования
@@ -194,6 +350,46 @@ func fixedBitStringDecode(data []byte, shift uint8, size int, alligned bool, val
 	return
 }
 
+// Encode BitString with fixed length, return data with appended value and bit shift for next encoding
+func fixedBitStringEncode(data []byte, shift uint8, size int, alligned bool, value []byte) (outData []byte, outShift uint8, err error) {
+	if len(value) != byteSizeCalculate(size) {
+		err = ErrorIncorrectLength
+		return
+	}
+	if size > 16 && alligned {
+		shift = 0
+	}
+	outData, outShift = putBitString(data, shift, value, size)
+	return
+}
+
+// Append size last bits of value to data (value is alligned by the end of octet)
+func putBitString(data []byte, shift uint8, value []byte, size int) (outData []byte, outShift uint8) {
+	return putBitRange(data, shift, value, len(value)*8-size, size)
+}
+
+// Append size bits of value starting from bit first to data
+func putBitRange(data []byte, shift uint8, value []byte, first int, size int) (outData []byte, outShift uint8) {
+	if shift == 0 && first%8 == 0 && size%8 == 0 {
+		return append(data, value[first/8:(first+size)/8]...), 0
+	}
+	for i := first; i < first+size; i++ {
+		if shift == 0 {
+			data = append(data, 0)
+		}
+		if value[i/8]&(0x80>>uint(i%8)) != 0 {
+			data[len(data)-1] |= 0x80 >> shift
+		}
+		shift = (shift + 1) % 8
+	}
+	return data, shift
+}
+
+// Number of octets for size bits
+func byteSizeCalculate(size int) int {
+	return (size + 7) / 8
+}
+
 // Принимает на вход данные, битовый сдвиг от начала байта, и остаток от деления на 8
 // Заполняет value значением bitString ивозвращает количество padding bit
 func bitStringDecode(data []byte, shift uint8, lastBits uint8, size int, value *[]byte) (padding uint8) {
@@ -206,30 +402,22 @@ func bitStringDecode(data []byte, shift uint8, lastBits uint8, size int, value *
 	if padding == 8 {
 		padding = 0
 	}
-	// Количество нулевых бит в начале (number of padding bits in the begin of result)
-	firstNulls := shift + padding
-	if firstNulls > 7 {
-		firstNulls -= 8
-	}
-	if firstNulls == 0 {
+	if shift == 0 && padding == 0 {
 		*value = append(*value, data[:size]...)
 		return
 	}
-	// Требуемый сдвиг от первого бита, что бы последний бит был на стыке октетов
-	if shift > firstNulls {
-		//Сдвиг влево
-		for i := 0; i < (size - 1); i++ {
-			*value = append(*value, data[i]<<(shift-firstNulls)|data[i+1]>>(8-shift-firstNulls))
-		}
-		(*value)[0] = (*value)[0] & (0xff >> firstNulls)
-
-	} else {
-		//Сдвиг вправо
-		*value = append(*value, (data[0]<<shift)>>firstNulls)
-		for i := 0; i < (size - 1); i++ {
-			*value = append(*value, data[i]<<(8-padding)|data[i+1]>>(padding))
+	// Количество бит и нулевых бит в начале результата (number of padding bits in the begin of result)
+	bitSize := 8*size - int(shift) - int(padding)
+	result := make([]byte, byteSizeCalculate(bitSize))
+	firstNulls := len(result)*8 - bitSize
+	for i := 0; i < bitSize; i++ {
+		src := int(shift) + i
+		if data[src/8]&(0x80>>uint(src%8)) != 0 {
+			dst := firstNulls + i
+			result[dst/8] |= 0x80 >> uint(dst%8)
 		}
 	}
+	*value = append(*value, result...)
 	return
 }
 
@@ -243,6 +431,3 @@ func fixedByteSizeCalculate(shift uint8, size int, outShift uint8) int {
 	}
 	return size
 }
-
-// Calculate size in byte for bitstring constrained
-// Return size (int), outData (after length field) and bit shift
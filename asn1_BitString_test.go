@@ -1,6 +1,7 @@
 package asn1_per
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 )
@@ -81,6 +82,48 @@ func TestFixedBitString(t *testing.T) {
 	}
 }
 
+// decoding of bit strings not alligned to the octet (shifted left or right),
+// previous versions returned wrong bits or extra octet for these cases
+func TestBitStringDecodeShift(t *testing.T) {
+	type result struct {
+		bitString []byte
+		outData   []byte
+		outShift  uint8
+	}
+	input := []byte{0xb5, 0x6c, 0xe3, 0x9a}
+	for _, test := range []struct {
+		name   string
+		size   int
+		shift  uint8
+		result result
+	}{
+		{name: `Octet_Shift_1`, size: 8, shift: 1, result: result{bitString: []byte{0x6a}, outData: []byte{0x6c, 0xe3, 0x9a}, outShift: 1}},
+		{name: `Bits_Shift_3`, size: 6, shift: 3, result: result{bitString: []byte{0x2a}, outData: []byte{0x6c, 0xe3, 0x9a}, outShift: 1}},
+		{name: `Bits_Shift_4`, size: 13, shift: 4, result: result{bitString: []byte{0x0a, 0xd9}, outData: []byte{0xe3, 0x9a}, outShift: 1}},
+		{name: `Two_Octets_Shift_5`, size: 16, shift: 5, result: result{bitString: []byte{0xad, 0x9c}, outData: []byte{0xe3, 0x9a}, outShift: 5}},
+	} {
+		res := result{}
+		b := NewFixedBitString(test.size, false)
+		var err error
+		res.outData, res.outShift, err = b.Decode(input, test.shift)
+		if err != nil {
+			t.Errorf("%s error fixed bit string decode %v", test.name, err)
+		}
+		res.bitString = b.Value
+		if !reflect.DeepEqual(test.result, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.result, res)
+			t.Fail()
+		}
+	}
+
+	// bit-field ends on the corner of octet
+	integer := NewConstrainedInteger(0, 7, true)
+	out, shift, err := integer.Decode([]byte{0x1d, 0xaa}, 5)
+	if err != nil || integer.Value != 5 || shift != 0 || !reflect.DeepEqual([]byte{0xaa}, out) {
+		t.Errorf("Bit_Field_Corner result is not expected: value %d, rest %x, shift %d (%v)", integer.Value, out, shift, err)
+	}
+}
+
 // BIT STRING with constrained length
 func TestConstrainedBitString(t *testing.T) {
 	var err error
@@ -175,6 +218,42 @@ func TestUnconstrainedBitString(t *testing.T) {
 				outShift:  0,
 			},
 		},
+		{
+			name:       `Test_Aligned_Without_Shift`,
+			allign:     true,
+			inputData:  []byte{0x0c, 0x4e, 0x19, 0x2b},
+			inputShift: 0,
+			result: result{
+				bitString: []byte{0x04, 0xe1},
+				bitSize:   12,
+				outData:   []byte{0x19, 0x2b},
+				outShift:  4,
+			},
+		},
+		{
+			name:       `Test_Unaligned`,
+			allign:     false,
+			inputData:  []byte{0xe1, 0x06, 0x7f},
+			inputShift: 3,
+			result: result{
+				bitString: []byte{0x33},
+				bitSize:   8,
+				outData:   []byte{0x7f},
+				outShift:  3,
+			},
+		},
+		{
+			name:       `Test_Empty`,
+			allign:     false,
+			inputData:  []byte{0xe0, 0x00},
+			inputShift: 3,
+			result: result{
+				bitString: []byte{},
+				bitSize:   0,
+				outData:   []byte{0x00},
+				outShift:  3,
+			},
+		},
 	} {
 		res := result{}
 		b := NewUnconstrainedBitString(test.allign)
@@ -190,3 +269,340 @@ func TestUnconstrainedBitString(t *testing.T) {
 		}
 	}
 }
+
+func TestFixedBitStringEncode(t *testing.T) {
+	var err error
+	type bitStringParameters struct {
+		size     int
+		alligned bool
+	}
+	type result struct {
+		outData  []byte
+		outShift uint8
+	}
+	for _, test := range []struct {
+		name       string
+		parameters bitStringParameters
+		bitString  []byte
+		inputData  []byte
+		inputShift uint8
+		result     result
+	}{
+		{
+			name: `Test_1`,
+			parameters: bitStringParameters{
+				size:     28,
+				alligned: true,
+			},
+			bitString:  []byte{0x0b, 0xfa, 0xf2, 0x06},
+			inputData:  []byte{0x80},
+			inputShift: 3,
+			result: result{
+				outData:  []byte{0x80, 0xbf, 0xaf, 0x20, 0x60},
+				outShift: 4,
+			},
+		},
+		{
+			name: `Test_2`,
+			parameters: bitStringParameters{
+				size:     5,
+				alligned: true,
+			},
+			bitString:  []byte{0x14},
+			inputData:  []byte{0x80},
+			inputShift: 5,
+			result: result{
+				outData:  []byte{0x85, 0x00},
+				outShift: 2,
+			},
+		},
+		{
+			name: `Test_3`,
+			parameters: bitStringParameters{
+				size:     28,
+				alligned: false,
+			},
+			bitString:  []byte{0x0b, 0xfa, 0xf2, 0x06},
+			inputData:  []byte{0x80},
+			inputShift: 4,
+			result: result{
+				outData:  []byte{0x8b, 0xfa, 0xf2, 0x06},
+				outShift: 0,
+			},
+		},
+	} {
+		res := result{}
+		b := NewFixedBitString(test.parameters.size, test.parameters.alligned)
+		b.Value = test.bitString
+		res.outData, res.outShift, err = b.Encode(test.inputData, test.inputShift)
+		if err != nil {
+			t.Errorf(`error fixed bit string encode`)
+		}
+		if !reflect.DeepEqual(test.result, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.result, res)
+			t.Fail()
+		}
+	}
+}
+
+func TestConstrainedBitStringEncode(t *testing.T) {
+	b := NewConstrainedBitString(1, 160, true)
+	b.Value = []byte{0x4e, 0x19, 0x69, 0x72}
+	b.Size = 32
+	out, shift, err := b.Encode([]byte{0x00}, 1)
+	if err != nil {
+		t.Errorf(`error constrained bit string encode`)
+	}
+	want := []byte{0x0f, 0x80, 0x4e, 0x19, 0x69, 0x72}
+	if !reflect.DeepEqual(want, out) || shift != 0 {
+		t.Errorf("result is not expected \n want %v, \n got  %v (shift %d)", want, out, shift)
+	}
+}
+
+func TestUnconstrainedBitStringEncode(t *testing.T) {
+	b := NewUnconstrainedBitString(true)
+	b.Value = []byte{0x4e, 0x19, 0x69, 0x72}
+	b.Size = 32
+	out, shift, err := b.Encode([]byte{0x00}, 1)
+	if err != nil {
+		t.Errorf(`error unconstrained bit string encode`)
+	}
+	want := []byte{0x00, 0x20, 0x4e, 0x19, 0x69, 0x72}
+	if !reflect.DeepEqual(want, out) || shift != 0 {
+		t.Errorf("result is not expected \n want %v, \n got  %v (shift %d)", want, out, shift)
+	}
+}
+
+// X.691 p.11.9.3.8: fragments of 64K, 48K, 32K and 16K bits
+func TestUnconstrainedBitStringFragmentation(t *testing.T) {
+	for _, test := range []struct {
+		size    int
+		headers []byte
+	}{
+		{size: 16383, headers: []byte{0xbf}},
+		{size: 16384, headers: []byte{0xc1, 0x00}},
+		{size: 32768, headers: []byte{0xc2, 0x00}},
+		{size: 49152, headers: []byte{0xc3, 0x00}},
+		{size: 65536, headers: []byte{0xc4, 0x00}},
+		{size: 65536 + 49152 + 5, headers: []byte{0xc4, 0xc3, 0x05}},
+		{size: 16384 + 3, headers: []byte{0xc1, 0x03}},
+	} {
+		for _, alligned := range []bool{true, false} {
+			value := make([]byte, byteSizeCalculate(test.size))
+			for i := range value {
+				value[i] = byte(0xa5 + 37*i)
+			}
+			value[0] &= 0xff >> (len(value)*8 - test.size)
+
+			b := NewUnconstrainedBitString(alligned)
+			b.Value = value
+			b.Size = test.size
+			encoded, shift, err := b.Encode([]byte{0x80}, 1)
+			if err != nil {
+				t.Fatalf("size %d: error encode %v", test.size, err)
+			}
+			// first length determinant
+			if alligned && encoded[1] != test.headers[0] || !alligned && encoded[0] != 0x80|test.headers[0]>>1 {
+				t.Errorf("size %d alligned %v: unexpected length determinant %x", test.size, alligned, encoded[:2])
+			}
+			d := NewUnconstrainedBitString(alligned)
+			out, outShift, err := d.Decode(encoded, 1)
+			if err != nil {
+				t.Fatalf("size %d alligned %v: error decode %v", test.size, alligned, err)
+			}
+			if d.Size != test.size || !reflect.DeepEqual(value, d.Value) || outShift != shift {
+				t.Errorf("size %d alligned %v: decoded value is not expected (size %d)", test.size, alligned, d.Size)
+			}
+			if outShift == 0 && len(out) != 0 || outShift != 0 && len(out) != 1 {
+				t.Errorf("size %d alligned %v: rest data %x", test.size, alligned, out)
+			}
+		}
+	}
+
+	// 16K bits fragment followed by 8 bits, aligned
+	data := append([]byte{0xc1}, make([]byte, 2048)...)
+	data[1] = 0x80
+	data = append(data, 0x08, 0x5a)
+	b := NewUnconstrainedBitString(true)
+	out, shift, err := b.Decode(data, 0)
+	if err != nil || b.Size != 16392 || len(b.Value) != 2049 || b.Value[0] != 0x80 || b.Value[2048] != 0x5a || len(out) != 0 || shift != 0 {
+		t.Errorf("fragmented result is not expected: size %d, err %v", b.Size, err)
+	}
+}
+
+// decode -> encode must be lossless for any size and shift
+func TestBitStringRoundTrip(t *testing.T) {
+	for _, alligned := range []bool{true, false} {
+		for size := 1; size <= 40; size++ {
+			for shift := uint8(0); shift < 8; shift++ {
+				var input []byte
+				if shift != 0 {
+					input = []byte{0xff << (8 - shift)}
+				}
+				value := make([]byte, byteSizeCalculate(size))
+				for i := range value {
+					value[i] = byte(0xa5 + 37*i)
+				}
+				value[0] &= 0xff >> (len(value)*8 - size)
+
+				b := NewConstrainedBitString(1, 40, alligned)
+				b.Value = value
+				b.Size = size
+				encoded, outShift, err := b.Encode(input, shift)
+				if err != nil {
+					t.Fatalf("size %d shift %d: error encode %v", size, shift, err)
+				}
+				d := NewConstrainedBitString(1, 40, alligned)
+				out, decShift, err := d.Decode(encoded, shift)
+				if err != nil {
+					t.Fatalf("size %d shift %d: error decode %v", size, shift, err)
+				}
+				if d.Size != size || !reflect.DeepEqual(value, d.Value) || decShift != outShift {
+					t.Errorf("size %d shift %d alligned %v: want %x, got %x (size %d)", size, shift, alligned, value, d.Value, d.Size)
+				}
+				if outShift == 0 && len(out) != 0 || outShift != 0 && len(out) != 1 {
+					t.Errorf("size %d shift %d alligned %v: rest data %x", size, shift, alligned, out)
+				}
+			}
+		}
+	}
+}
+
+// sizes at 16K and 64K boundaries, length determinant with fragmentation for ub >= 64K
+func TestLargeBitString(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		bits   Codec
+		size   int
+		header []byte // first octets of encoding
+		length int    // length of encoding
+	}{
+		{name: `Fixed_16K`, bits: NewFixedBitString(16384, true), size: 16384, length: 2048},
+		{name: `Fixed_64K_Minus_1`, bits: NewFixedBitString(65535, true), size: 65535, length: 8192},
+		{name: `Fixed_64K`, bits: NewFixedBitString(65536, true), size: 65536, header: []byte{0xc4}, length: 8194},
+		{name: `Fixed_64K_Unaligned`, bits: NewFixedBitString(65536, false), size: 65536, header: []byte{0xc4}, length: 8194},
+		{name: `Constrained_16K`, bits: NewConstrainedBitString(0, 65535, true), size: 16384, header: []byte{0x40, 0x00}, length: 2050},
+		{name: `Constrained_64K_16K`, bits: NewConstrainedBitString(0, 65536, true), size: 16384, header: []byte{0xc1}, length: 2050},
+		{name: `Constrained_64K_16K_Plus_3`, bits: NewConstrainedBitString(0, 65536, true), size: 16387, header: []byte{0xc1}, length: 2051},
+		{name: `Constrained_64K_Max`, bits: NewConstrainedBitString(1, 70000, false), size: 70000, header: []byte{0xc4}, length: 8753},
+	} {
+		value := make([]byte, byteSizeCalculate(test.size))
+		for i := range value {
+			value[i] = byte(0xa5 + 37*i)
+		}
+		value[0] &= 0xff >> (len(value)*8 - test.size)
+		var decoded *[]byte
+		switch b := test.bits.(type) {
+		case *FixedBitString:
+			b.Value = value
+			decoded = &b.Value
+		case *ConstrainedBitString:
+			b.Value = value
+			b.Size = test.size
+			decoded = &b.Value
+		}
+		encoded, shift, err := test.bits.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode %v", test.name, err)
+		}
+		if len(encoded) != test.length || !bytes.HasPrefix(encoded, test.header) {
+			t.Errorf("%s encoding is not expected: length %d, header %x", test.name, len(encoded), encoded[:2])
+		}
+		*decoded = nil
+		_, outShift, err := test.bits.Decode(encoded, 0)
+		if err != nil || outShift != shift || !reflect.DeepEqual(value, *decoded) {
+			t.Errorf("%s decoding is not expected (%v)", test.name, err)
+		}
+	}
+}
+
+// SIZE constraint with extension marker
+func TestExtensibleBitString(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+
+	fixed := NewExtensibleFixedBitString(32, true)
+	fixed.Value = []byte{0x01, 0x02, 0x03, 0x04}
+	res := result{}
+	res.data, res.shift, res.err = fixed.Encode(nil, 0)
+	want := result{data: []byte{0x00, 0x01, 0x02, 0x03, 0x04}}
+	if !reflect.DeepEqual(want, res) {
+		t.Errorf("Fixed_Root result is not expected \n want %v, \n got  %v", want, res)
+	}
+	fixed.Value, fixed.BitLength = nil, 0
+	if _, _, err := fixed.Decode(res.data, 0); err != nil || fixed.BitLength != 32 || !reflect.DeepEqual([]byte{0x01, 0x02, 0x03, 0x04}, fixed.Value) {
+		t.Errorf("Fixed_Root decoding is not expected %x, %d (%v)", fixed.Value, fixed.BitLength, err)
+	}
+
+	fixed.Value = []byte{0xab}
+	fixed.BitLength = 8
+	res.data, res.shift, res.err = fixed.Encode(nil, 0)
+	want = result{data: []byte{0x80, 0x08, 0xab}}
+	if !reflect.DeepEqual(want, res) {
+		t.Errorf("Fixed_Extension result is not expected \n want %v, \n got  %v", want, res)
+	}
+	fixed.Value, fixed.BitLength = nil, 0
+	if _, _, err := fixed.Decode(res.data, 0); err != nil || fixed.BitLength != 8 || !reflect.DeepEqual([]byte{0xab}, fixed.Value) {
+		t.Errorf("Fixed_Extension decoding is not expected %x, %d (%v)", fixed.Value, fixed.BitLength, err)
+	}
+
+	// empty Value outside of root
+	fixed.Value = []byte{}
+	fixed.BitLength = 0
+	res.data, res.shift, res.err = fixed.Encode(nil, 0)
+	want = result{data: []byte{0x80, 0x00}}
+	if !reflect.DeepEqual(want, res) {
+		t.Errorf("Fixed_Extension_Empty result is not expected \n want %v, \n got  %v", want, res)
+	}
+	fixed.Value, fixed.BitLength = nil, -1
+	if _, _, err := fixed.Decode(res.data, 0); err != nil || fixed.BitLength != 0 || len(fixed.Value) != 0 {
+		t.Errorf("Fixed_Extension_Empty decoding is not expected %x, %d (%v)", fixed.Value, fixed.BitLength, err)
+	}
+
+	// Value one bit shorter than root size
+	fixed.Value = []byte{0x00, 0x81, 0x01, 0x82}
+	fixed.BitLength = 31
+	res.data, res.shift, res.err = fixed.Encode(nil, 0)
+	want = result{data: []byte{0x80, 0x1f, 0x01, 0x02, 0x03, 0x04}, shift: 7}
+	if !reflect.DeepEqual(want, res) {
+		t.Errorf("Fixed_Extension_Short result is not expected \n want %v, \n got  %v", want, res)
+	}
+	fixed.Value, fixed.BitLength = nil, 0
+	if _, _, err := fixed.Decode(res.data, 0); err != nil || fixed.BitLength != 31 || !reflect.DeepEqual([]byte{0x00, 0x81, 0x01, 0x82}, fixed.Value) {
+		t.Errorf("Fixed_Extension_Short decoding is not expected %x, %d (%v)", fixed.Value, fixed.BitLength, err)
+	}
+
+	constrained := NewExtensibleConstrainedBitString(1, 8, true)
+	constrained.Value = []byte{0x05}
+	constrained.Size = 3
+	res.data, res.shift, res.err = constrained.Encode(nil, 0)
+	want = result{data: []byte{0x2a}, shift: 7}
+	if !reflect.DeepEqual(want, res) {
+		t.Errorf("Constrained_Root result is not expected \n want %v, \n got  %v", want, res)
+	}
+	constrained.Value, constrained.Size = nil, 0
+	if _, _, err := constrained.Decode(res.data, 0); err != nil || constrained.Size != 3 || !reflect.DeepEqual([]byte{0x05}, constrained.Value) {
+		t.Errorf("Constrained_Root decoding is not expected %x, %d (%v)", constrained.Value, constrained.Size, err)
+	}
+
+	constrained.Value = []byte{0x01, 0xff}
+	constrained.Size = 9
+	res.data, res.shift, res.err = constrained.Encode(nil, 0)
+	want = result{data: []byte{0x80, 0x09, 0xff, 0x80}, shift: 1}
+	if !reflect.DeepEqual(want, res) {
+		t.Errorf("Constrained_Extension result is not expected \n want %v, \n got  %v", want, res)
+	}
+	constrained.Value, constrained.Size = nil, 0
+	if _, _, err := constrained.Decode(res.data, 0); err != nil || constrained.Size != 9 || !reflect.DeepEqual([]byte{0x01, 0xff}, constrained.Value) {
+		t.Errorf("Constrained_Extension decoding is not expected %x, %d (%v)", constrained.Value, constrained.Size, err)
+	}
+
+	// not extensible constraint rejects size outside of root
+	constrained.Extensible = false
+	if _, _, err := constrained.Encode(nil, 0); err != ErrorIncorrectLength {
+		t.Errorf("Constrained_Not_Extensible result is not expected \n want %v, \n got  %v", ErrorIncorrectLength, err)
+	}
+}
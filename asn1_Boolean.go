@@ -0,0 +1,42 @@
+package asn1_per
+
+// BOOLEAN Type (X.691 p.12), encoded as single bit
+
+type Boolean struct {
+	Value bool
+}
+
+func NewBoolean() *Boolean {
+	return &Boolean{
+		Value: false,
+	}
+}
+
+func (b *Boolean) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	var bit uint64
+	bit, outData, outShift, err = getBits(data, shift, 1)
+	b.Value = bit == 1
+	return
+}
+
+func (b *Boolean) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	outData, outShift = putBits(data, shift, boolToBit(b.Value), 1)
+	return
+}
+
+// Decode from current position of BitReader
+func (b *Boolean) DecodeFrom(r *BitReader) error {
+	return r.decode(b.Decode)
+}
+
+// Encode to current position of BitWriter
+func (b *Boolean) EncodeTo(w *BitWriter) error {
+	return w.encode(b.Encode)
+}
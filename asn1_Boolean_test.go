@@ -0,0 +1,64 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBoolean(t *testing.T) {
+	type result struct {
+		value bool
+		data  []byte
+		shift uint8
+	}
+	for _, test := range []struct {
+		name  string
+		input []byte
+		shift uint8
+		want  result
+	}{
+		{
+			name:  `True`,
+			input: []byte{0x10, 0xff},
+			shift: 3,
+			want: result{
+				value: true,
+				data:  []byte{0x10, 0xff},
+				shift: 4,
+			},
+		},
+		{
+			name:  `False_Last_Bit`,
+			input: []byte{0xfe, 0xff},
+			shift: 7,
+			want: result{
+				value: false,
+				data:  []byte{0xff},
+				shift: 0,
+			},
+		},
+	} {
+		var err error
+		res := result{}
+		b := NewBoolean()
+		res.data, res.shift, err = b.Decode(test.input, test.shift)
+		if err != nil {
+			t.Errorf("%s error decode boolean", test.name)
+		}
+		res.value = b.Value
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+		// encode the same bit after the same preceding bits
+		prefix := []byte{test.input[0] & (0xff << (8 - test.shift))}
+		encoded, shift, err := b.Encode(prefix, test.shift)
+		if err != nil {
+			t.Errorf("%s error encode boolean", test.name)
+		}
+		want := test.input[0] & (0xff << (8 - test.shift - 1))
+		if len(encoded) != 1 || encoded[0] != want || shift != res.shift {
+			t.Errorf("%s encoding is not expected \n want %x, \n got  %x", test.name, want, encoded)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package asn1_per
+
+// CHOICE Type (X.691 p.23)
+
+type Choice struct {
+	Alternatives []Codec // root alternatives
+	Extensions   []Codec // extension alternatives
+	Extensible   bool    // CHOICE with extension marker "..."
+	Alligned     bool
+	Index        int    // index of chosen alternative, extension alternatives follow root alternatives
+	Unknown      []byte // open type octets of unknown extension alternative
+}
+
+func NewChoice(alligned bool, alternatives ...Codec) *Choice {
+	return &Choice{
+		Alternatives: alternatives,
+		Alligned:     alligned,
+	}
+}
+
+// CHOICE with extension marker, unknown extension alternatives are decoded as raw octets (Unknown field)
+func NewExtensibleChoice(alligned bool, alternatives []Codec, extensions ...Codec) *Choice {
+	return &Choice{
+		Alternatives: alternatives,
+		Extensions:   extensions,
+		Extensible:   true,
+		Alligned:     alligned,
+	}
+}
+
+// Chosen alternative (nil for unknown extension alternative)
+func (c *Choice) Value() Codec {
+	if c.Index < 0 {
+		return nil
+	}
+	if c.Index < len(c.Alternatives) {
+		return c.Alternatives[c.Index]
+	}
+	if c.Index-len(c.Alternatives) < len(c.Extensions) {
+		return c.Extensions[c.Index-len(c.Alternatives)]
+	}
+	return nil
+}
+
+// Decode index and chosen alternative
+func (c *Choice) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(c.Alternatives) == 0 {
+		err = ErrorInputParameters
+		return
+	}
+	var extended uint64
+	if c.Extensible {
+		extended, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+	}
+	c.Unknown = nil
+	if extended == 1 {
+		return c.extensionDecode(data, shift)
+	}
+	c.Index = 0
+	if len(c.Alternatives) > 1 {
+		c.Index, data, shift, err = constrainedWholeNumber(data, shift, len(c.Alternatives), c.Alligned)
+		if err != nil {
+			return
+		}
+		if c.Index >= len(c.Alternatives) {
+			err = ErrorIncorrectDecode
+			return
+		}
+	}
+	return c.Alternatives[c.Index].Decode(data, shift)
+}
+
+// Encode index and chosen alternative
+func (c *Choice) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(c.Alternatives) == 0 || c.Index < 0 {
+		err = ErrorInputParameters
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	extended := c.Index >= len(c.Alternatives)
+	if c.Extensible {
+		data, shift = putBits(data, shift, boolToBit(extended), 1)
+	} else if extended {
+		err = ErrorInputParameters
+		return
+	}
+	if extended {
+		return c.extensionEncode(data, shift)
+	}
+	data, shift, err = encodeConstrainedWholeNumber(data, shift, c.Index, len(c.Alternatives), c.Alligned)
+	if err != nil {
+		return
+	}
+	return c.Alternatives[c.Index].Encode(data, shift)
+}
+
+// Decode from current position of BitReader
+func (c *Choice) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *Choice) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
+}
+
+// extension alternative: normally small index and open type
+func (c *Choice) extensionDecode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	var (
+		index int
+		value []byte
+	)
+	index, data, shift, err = DecodeNormallySmallNumber(data, shift, c.Alligned)
+	if err != nil {
+		return
+	}
+	value, outData, outShift, err = openTypeDecode(data, shift, c.Alligned)
+	if err != nil {
+		return
+	}
+	c.Index = len(c.Alternatives) + index
+	// unknown extension alternative (added in the later version of specification)
+	if index >= len(c.Extensions) {
+		c.Unknown = value
+		return
+	}
+	_, _, err = c.Extensions[index].Decode(value, 0)
+	return
+}
+
+func (c *Choice) extensionEncode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	index := c.Index - len(c.Alternatives)
+	data, shift, err = EncodeNormallySmallNumber(data, shift, index, c.Alligned)
+	if err != nil {
+		return
+	}
+	value := c.Unknown
+	if index < len(c.Extensions) {
+		value, err = completeEncoding(c.Extensions[index])
+		if err != nil {
+			return
+		}
+	} else if len(value) == 0 {
+		err = ErrorInputParameters
+		return
+	}
+	return openTypeEncode(data, shift, value, c.Alligned)
+}
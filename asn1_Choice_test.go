@@ -0,0 +1,95 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+//	CHOICE{
+//		a INTEGER (0..255),
+//		b OCTET STRING (SIZE (2)),
+//		c INTEGER (0..7),
+//		...,
+//		d INTEGER (0..15)
+//	}
+func TestChoice(t *testing.T) {
+	type result struct {
+		index   int
+		value   interface{}
+		unknown []byte
+	}
+	for _, test := range []struct {
+		name       string
+		extensions bool
+		encoded    []byte
+		shift      uint8
+		want       result
+	}{
+		{
+			name:       `Root`,
+			extensions: true,
+			encoded:    []byte{0x22, 0x46, 0x80},
+			shift:      3,
+			want: result{
+				index: 1,
+				value: []byte{0x12, 0x34},
+			},
+		},
+		{
+			name:       `Extension`,
+			extensions: true,
+			encoded:    []byte{0x80, 0x01, 0x90},
+			shift:      0,
+			want: result{
+				index: 3,
+				value: 9,
+			},
+		},
+		{
+			name:       `Unknown_Extension`,
+			extensions: false,
+			encoded:    []byte{0x80, 0x01, 0x90},
+			shift:      0,
+			want: result{
+				index:   3,
+				unknown: []byte{0x90},
+			},
+		},
+	} {
+		a := NewConstrainedInteger(0, 255, true)
+		b := NewFixedOctetString(2, true)
+		c := NewConstrainedInteger(0, 7, true)
+		d := NewConstrainedInteger(0, 15, true)
+		var extensions []Codec
+		if test.extensions {
+			extensions = append(extensions, d)
+		}
+		choice := NewExtensibleChoice(true, []Codec{a, b, c}, extensions...)
+		_, shift, err := choice.Decode(test.encoded, 0)
+		if err != nil {
+			t.Fatalf("%s error decode choice %v", test.name, err)
+		}
+		res := result{
+			index:   choice.Index,
+			unknown: choice.Unknown,
+		}
+		switch v := choice.Value().(type) {
+		case *FixedOctetString:
+			res.value = v.Value
+		case *ConstrainedInteger:
+			res.value = v.Value
+		}
+		if !reflect.DeepEqual(test.want, res) || shift != test.shift {
+			t.Logf("%s result is not expected \n want %v, \n got  %v (shift %d)", test.name, test.want, res, shift)
+			t.Fail()
+		}
+
+		encoded, shift, err := choice.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode choice %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.encoded, encoded) || shift != test.shift {
+			t.Errorf("%s encoding is not expected \n want %v, \n got  %v", test.name, test.encoded, encoded)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package asn1_per
+
+// ENUMERATED Type (X.691 p.14)
+
+type Enumerated struct {
+	Root       int  // number of root enumerations
+	Extensions int  // number of known extension enumerations
+	Extensible bool // ENUMERATED with extension marker "..."
+	Alligned   bool
+	Value      int  // enumeration index, extension values follow root values
+	Unknown    bool // Value is unknown extension value
+}
+
+func NewEnumerated(root int, alligned bool) *Enumerated {
+	return &Enumerated{
+		Root:     root,
+		Alligned: alligned,
+	}
+}
+
+// ENUMERATED with extension marker, unknown extension values are reported by Unknown field
+func NewExtensibleEnumerated(root int, extensions int, alligned bool) *Enumerated {
+	return &Enumerated{
+		Root:       root,
+		Extensions: extensions,
+		Extensible: true,
+		Alligned:   alligned,
+	}
+}
+
+func (e *Enumerated) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if e.Root < 1 {
+		err = ErrorInputParameters
+		return
+	}
+	var extended uint64
+	if e.Extensible {
+		extended, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+	}
+	e.Unknown = false
+	// extension value - normally small non-negative whole number
+	if extended == 1 {
+		var index int
+		index, outData, outShift, err = DecodeNormallySmallNumber(data, shift, e.Alligned)
+		if err != nil {
+			return
+		}
+		e.Value = e.Root + index
+		e.Unknown = index >= e.Extensions
+		return
+	}
+	// root value - constrained whole number
+	e.Value = 0
+	if e.Root == 1 {
+		return data, shift, nil
+	}
+	e.Value, outData, outShift, err = constrainedWholeNumber(data, shift, e.Root, e.Alligned)
+	if err == nil && e.Value >= e.Root {
+		err = ErrorIncorrectDecode
+	}
+	return
+}
+
+func (e *Enumerated) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if e.Root < 1 || e.Value < 0 || (!e.Extensible && e.Value >= e.Root) {
+		err = ErrorInputParameters
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if e.Extensible {
+		data, shift = putBits(data, shift, boolToBit(e.Value >= e.Root), 1)
+	}
+	if e.Value >= e.Root {
+		return EncodeNormallySmallNumber(data, shift, e.Value-e.Root, e.Alligned)
+	}
+	return encodeConstrainedWholeNumber(data, shift, e.Value, e.Root, e.Alligned)
+}
+
+// Decode from current position of BitReader
+func (e *Enumerated) DecodeFrom(r *BitReader) error {
+	return r.decode(e.Decode)
+}
+
+// Encode to current position of BitWriter
+func (e *Enumerated) EncodeTo(w *BitWriter) error {
+	return w.encode(e.Encode)
+}
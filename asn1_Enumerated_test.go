@@ -0,0 +1,82 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnumerated(t *testing.T) {
+	type result struct {
+		value   int
+		unknown bool
+	}
+	type parameters struct {
+		root       int
+		extensions int
+		extensible bool
+	}
+	for _, test := range []struct {
+		name    string
+		param   parameters
+		encoded []byte
+		shift   uint8
+		want    result
+	}{
+		{
+			name:    `Root`,
+			param:   parameters{root: 5},
+			encoded: []byte{0x80},
+			shift:   3,
+			want:    result{value: 4},
+		},
+		{
+			name:    `Single`,
+			param:   parameters{root: 1},
+			encoded: nil,
+			shift:   0,
+			want:    result{value: 0},
+		},
+		{
+			name:    `Extensible_Root`,
+			param:   parameters{root: 3, extensions: 1, extensible: true},
+			encoded: []byte{0x40},
+			shift:   3,
+			want:    result{value: 2},
+		},
+		{
+			name:    `Extension`,
+			param:   parameters{root: 3, extensions: 1, extensible: true},
+			encoded: []byte{0x80},
+			shift:   0,
+			want:    result{value: 3},
+		},
+		{
+			name:    `Unknown_Extension`,
+			param:   parameters{root: 3, extensions: 1, extensible: true},
+			encoded: []byte{0x85},
+			shift:   0,
+			want:    result{value: 8, unknown: true},
+		},
+	} {
+		e := NewEnumerated(test.param.root, true)
+		if test.param.extensible {
+			e = NewExtensibleEnumerated(test.param.root, test.param.extensions, true)
+		}
+		_, shift, err := e.Decode(test.encoded, 0)
+		if err != nil {
+			t.Fatalf("%s error decode enumerated %v", test.name, err)
+		}
+		res := result{value: e.Value, unknown: e.Unknown}
+		if !reflect.DeepEqual(test.want, res) || shift != test.shift {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+		encoded, shift, err := e.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode enumerated %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.encoded, encoded) || shift != test.shift {
+			t.Errorf("%s encoding is not expected \n want %v, \n got  %v", test.name, test.encoded, encoded)
+		}
+	}
+}
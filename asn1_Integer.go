@@ -1,12 +1,18 @@
 package asn1_per
 
+import (
+	"math"
+)
+
 // INTEGER Type Сonstrained
 
 type ConstrainedInteger struct {
-	LowerBand int
-	UpperBand int
-	Alligned  bool
-	Value     int
+	LowerBand  int
+	UpperBand  int
+	Alligned   bool
+	Extensible bool // INTEGER (lb..ub, ...), values outside of root are encoded as unconstrained
+	RawOffset  bool // Value is offset from LowerBand (behaviour of previous versions)
+	Value      int
 }
 
 func NewConstrainedInteger(lb int, ub int, alligned bool) *ConstrainedInteger {
@@ -18,6 +24,17 @@ func NewConstrainedInteger(lb int, ub int, alligned bool) *ConstrainedInteger {
 	}
 }
 
+// INTEGER with extensible constraint (lb..ub, ...)
+func NewExtensibleConstrainedInteger(lb int, ub int, alligned bool) *ConstrainedInteger {
+	return &ConstrainedInteger{
+		LowerBand:  lb,
+		UpperBand:  ub,
+		Alligned:   alligned,
+		Extensible: true,
+		Value:      0,
+	}
+}
+
 func (c *ConstrainedInteger) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
 	if c.LowerBand >= c.UpperBand {
 		err = ErrorInputParameters
@@ -27,80 +44,153 @@ func (c *ConstrainedInteger) Decode(data []byte, shift uint8) (outData []byte, o
 		err = ErrorBufferToShort
 		return
 	}
+	if c.Extensible {
+		var extended uint64
+		extended, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+		// value outside of root
+		if extended == 1 {
+			var value int64
+			value, outData, outShift, err = unconstrainedWholeNumber(data, shift, c.Alligned)
+			c.Value = int(value)
+			return
+		}
+		if len(data) == 0 {
+			err = ErrorBufferToShort
+			return
+		}
+	}
 
-	rang := c.UpperBand - c.LowerBand + 1
+	// range ub - lb + 1 may overflow int, span = ub - lb is exact in uint64
+	span := uint64(c.UpperBand) - uint64(c.LowerBand)
 
-	if !c.Alligned {
-		c.Value, outData, outShift, err = unalignedConstrainedWholeNumber(data, shift, rang)
+	var offset uint64
+	offset, outData, outShift, err = constrainedWholeNumber64(data, shift, span, c.Alligned)
+	if err != nil {
 		return
 	}
-
-	// the bit filed case
-	if rang < 256 {
-		c.Value, outData, outShift, err = bitFieldCase(data, shift, rang)
+	if offset > span {
+		err = ErrorConstraintViolation
 		return
 	}
-	// the one octet case
-	if rang == 256 {
-		if shift != 0 {
-			outShift = 0
-			data = data[1:]
+	if c.RawOffset {
+		if offset > math.MaxInt64 {
+			err = ErrorIncorrectDecode
+			return
 		}
-		c.Value, outData, err = oneOctetCase(data)
+		c.Value = int(offset)
 		return
 	}
-	// the two-octet case
-	if rang > 256 && rang < 65536 {
-		if shift != 0 {
-			outShift = 0
-			data = data[1:]
+	c.Value = int(uint64(c.LowerBand) + offset)
+	return
+}
+
+// Encode Value, data - previously encoded data, shift - number of used bits in the last octet of data
+func (c *ConstrainedInteger) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if c.LowerBand >= c.UpperBand {
+		err = ErrorInputParameters
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	span := uint64(c.UpperBand) - uint64(c.LowerBand)
+	offset := uint64(c.Value) - uint64(c.LowerBand)
+	outside := c.Value < c.LowerBand || c.Value > c.UpperBand
+	if c.RawOffset {
+		offset = uint64(c.Value)
+		outside = c.Value < 0 || offset > span
+	}
+	if c.Extensible {
+		data, shift = putBits(data, shift, boolToBit(outside), 1)
+		// value outside of root
+		if outside {
+			return encodeUnconstrainedWholeNumber(data, shift, int64(c.Value), c.Alligned)
 		}
-		c.Value, outData, err = twoOctetCase(data)
+	}
+	if outside {
+		err = ErrorConstraintViolation
 		return
 	}
-	// other variants
-	var lengthSize uint8
-	lengthSize, err = lengthSizeCalculate(rang)
-	if err != nil {
+	return encodeConstrainedWholeNumber64(data, shift, offset, span, c.Alligned)
+}
+
+// Decode from current position of BitReader
+func (c *ConstrainedInteger) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *ConstrainedInteger) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
+}
+
+// INTEGER Type Сonstrained with unsigned 64-bit bounds (lb..ub up to 18446744073709551615)
+
+type UnsignedConstrainedInteger struct {
+	LowerBand uint64
+	UpperBand uint64
+	Alligned  bool
+	Value     uint64
+}
+
+func NewUnsignedConstrainedInteger(lb uint64, ub uint64, alligned bool) *UnsignedConstrainedInteger {
+	return &UnsignedConstrainedInteger{
+		LowerBand: lb,
+		UpperBand: ub,
+		Alligned:  alligned,
+		Value:     lb,
+	}
+}
+
+func (c *UnsignedConstrainedInteger) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if c.LowerBand >= c.UpperBand {
+		err = ErrorInputParameters
 		return
 	}
-	// length mo than 256 octets is to big and has not of any practical interest
-	// in that case lenght is not alligned of octets
-	if len(data) < 2 {
+	if len(data) == 0 {
 		err = ErrorBufferToShort
 		return
 	}
-	// low band of integer length = 1
-	size := ((data[0]<<shift | data[1]>>(8-shift)) >> (8 - lengthSize)) + 1
-	data = data[1:]
-	shift += lengthSize
-	if shift > 8 {
-		data = data[1:]
+	span := c.UpperBand - c.LowerBand
+	var offset uint64
+	offset, outData, outShift, err = constrainedWholeNumber64(data, shift, span, c.Alligned)
+	if err != nil {
+		return
 	}
-	if len(data) < int(size) {
-		err = ErrorIncorrectLength
+	if offset > span {
+		err = ErrorConstraintViolation
 		return
 	}
-	c.Value = int(parseInt64(data[:size]))
-	outData = data[size:]
+	c.Value = c.LowerBand + offset
 	return
 }
 
-// return number of bits nedded for length (number of bytes) coding
-func lengthSizeCalculate(rang int) (size uint8, err error) {
-	// bytes neds for coding integer value
-	sizeinBits := sizeInBits(rang)
-	sizeinBytes := sizeinBits / 8
-	if sizeinBits%8 != 0 {
-		sizeinBytes += 1
-	}
-	// not of any practical interest
-	if sizeinBytes > 127 {
+func (c *UnsignedConstrainedInteger) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if c.LowerBand >= c.UpperBand {
 		err = ErrorInputParameters
 		return
 	}
-	size = uint8(sizeInBits(sizeinBytes))
-	return
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if c.Value < c.LowerBand || c.Value > c.UpperBand {
+		err = ErrorConstraintViolation
+		return
+	}
+	return encodeConstrainedWholeNumber64(data, shift, c.Value-c.LowerBand, c.UpperBand-c.LowerBand, c.Alligned)
+}
+
+// Decode from current position of BitReader
+func (c *UnsignedConstrainedInteger) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *UnsignedConstrainedInteger) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
 }
 
 func parseInt64(in []byte) (val int64) {
@@ -113,3 +203,101 @@ func parseInt64(in []byte) (val int64) {
 	val >>= 64 - uint8(len(in))*8
 	return
 }
+
+// INTEGER Type Semi-constrained (lb..MAX)
+
+type SemiConstrainedInteger struct {
+	LowerBand int
+	Alligned  bool
+	Value     int
+}
+
+func NewSemiConstrainedInteger(lb int, alligned bool) *SemiConstrainedInteger {
+	return &SemiConstrainedInteger{
+		LowerBand: lb,
+		Alligned:  alligned,
+		Value:     lb,
+	}
+}
+
+func (c *SemiConstrainedInteger) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	var offset uint64
+	offset, outData, outShift, err = semiConstrainedWholeNumber(data, shift, c.Alligned)
+	if err != nil {
+		return
+	}
+	// lower band + offset must fit in the integer type
+	if offset > uint64(math.MaxInt64)-uint64(c.LowerBand) {
+		err = ErrorIncorrectDecode
+		return
+	}
+	c.Value = int(uint64(c.LowerBand) + offset)
+	return
+}
+
+func (c *SemiConstrainedInteger) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if c.Value < c.LowerBand {
+		err = ErrorConstraintViolation
+		return
+	}
+	return encodeSemiConstrainedWholeNumber(data, shift, uint64(c.Value)-uint64(c.LowerBand), c.Alligned)
+}
+
+// Decode from current position of BitReader
+func (c *SemiConstrainedInteger) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *SemiConstrainedInteger) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
+}
+
+// INTEGER Type Unconstrained
+
+type UnconstrainedInteger struct {
+	Alligned bool
+	Value    int
+}
+
+func NewUnconstrainedInteger(alligned bool) *UnconstrainedInteger {
+	return &UnconstrainedInteger{
+		Alligned: alligned,
+		Value:    0,
+	}
+}
+
+func (c *UnconstrainedInteger) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	var value int64
+	value, outData, outShift, err = unconstrainedWholeNumber(data, shift, c.Alligned)
+	c.Value = int(value)
+	return
+}
+
+func (c *UnconstrainedInteger) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	return encodeUnconstrainedWholeNumber(data, shift, int64(c.Value), c.Alligned)
+}
+
+// Decode from current position of BitReader
+func (c *UnconstrainedInteger) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *UnconstrainedInteger) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
+}
@@ -1,6 +1,7 @@
 package asn1_per
 
 import (
+	"math"
 	"reflect"
 	"testing"
 )
@@ -98,3 +99,607 @@ func TestIntegerConstrain(t *testing.T) {
 		}
 	}
 }
+
+func TestIntegerConstrainEncode(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+	type intParam struct {
+		lb     int
+		ub     int
+		allign bool
+	}
+	for _, test := range []struct {
+		name  string
+		param intParam
+		value int
+		input []byte
+		shift uint8
+		want  result
+	}{
+		{
+			name: `Test_Bits`,
+			param: intParam{
+				lb:     0,
+				ub:     15,
+				allign: true,
+			},
+			value: 5,
+			input: []byte{0x00},
+			shift: 4,
+			want: result{
+				data:  []byte{0x05},
+				shift: 0,
+			},
+		},
+		{
+			name: `Test_Bits_1`,
+			param: intParam{
+				lb:     0,
+				ub:     15,
+				allign: true,
+			},
+			value: 1,
+			input: []byte{0x00},
+			shift: 2,
+			want: result{
+				data:  []byte{0x04},
+				shift: 6,
+			},
+		},
+		{
+			name: `Test_Octet`,
+			param: intParam{
+				lb:     0,
+				ub:     255,
+				allign: true,
+			},
+			value: 11,
+			input: []byte{0x80},
+			shift: 4,
+			want: result{
+				data:  []byte{0x80, 0x0b},
+				shift: 0,
+			},
+		},
+		{
+			name: `Test_Two_Octets`,
+			param: intParam{
+				lb:     10,
+				ub:     1000,
+				allign: true,
+			},
+			value: 310,
+			input: []byte{0xc0},
+			shift: 2,
+			want: result{
+				data:  []byte{0xc0, 0x01, 0x2c},
+				shift: 0,
+			},
+		},
+		{
+			name: `Test_Octets`,
+			param: intParam{
+				lb:     0,
+				ub:     16777215,
+				allign: true,
+			},
+			value: 760352,
+			input: nil,
+			shift: 0,
+			want: result{
+				data:  []byte{0x80, 0x0b, 0x9a, 0x20},
+				shift: 0,
+			},
+		},
+		{
+			name: `Test_Unaligned`,
+			param: intParam{
+				lb:     0,
+				ub:     131070,
+				allign: false,
+			},
+			value: 102665,
+			input: []byte{0x08},
+			shift: 6,
+			want: result{
+				data:  []byte{0x0b, 0x22, 0x12},
+				shift: 7,
+			},
+		},
+		{
+			name: `Test_Out_Of_Range`,
+			param: intParam{
+				lb:     1,
+				ub:     15,
+				allign: true,
+			},
+			value: 0,
+			input: nil,
+			shift: 0,
+			want: result{
+				err: ErrorConstraintViolation,
+			},
+		},
+	} {
+		res := result{}
+		val := NewConstrainedInteger(test.param.lb, test.param.ub, test.param.allign)
+		val.Value = test.value
+		res.data, res.shift, res.err = val.Encode(test.input, test.shift)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+	}
+}
+
+func TestExtensibleIntegerConstrain(t *testing.T) {
+	type intParam struct {
+		lb     int
+		ub     int
+		allign bool
+	}
+	for _, test := range []struct {
+		name    string
+		param   intParam
+		value   int
+		encoded []byte
+		shift   uint8
+	}{
+		{
+			name:    `Root`,
+			param:   intParam{lb: 0, ub: 65535, allign: true},
+			value:   300,
+			encoded: []byte{0x00, 0x01, 0x2c},
+			shift:   0,
+		},
+		{
+			name:    `Extension`,
+			param:   intParam{lb: 0, ub: 65535, allign: true},
+			value:   70000,
+			encoded: []byte{0x80, 0x03, 0x01, 0x11, 0x70},
+			shift:   0,
+		},
+		{
+			name:    `Extension_Negative`,
+			param:   intParam{lb: 0, ub: 65535, allign: true},
+			value:   -1,
+			encoded: []byte{0x80, 0x01, 0xff},
+			shift:   0,
+		},
+		{
+			name:    `Unaligned_Root`,
+			param:   intParam{lb: 0, ub: 7, allign: false},
+			value:   5,
+			encoded: []byte{0x50},
+			shift:   4,
+		},
+		{
+			name:    `Unaligned_Extension`,
+			param:   intParam{lb: 0, ub: 7, allign: false},
+			value:   9,
+			encoded: []byte{0x80, 0x84, 0x80},
+			shift:   1,
+		},
+	} {
+		val := NewExtensibleConstrainedInteger(test.param.lb, test.param.ub, test.param.allign)
+		val.Value = test.value
+		encoded, shift, err := val.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode extensible integer %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.encoded, encoded) || shift != test.shift {
+			t.Errorf("%s encoding is not expected \n want %v, \n got  %v (shift %d)", test.name, test.encoded, encoded, shift)
+		}
+		val = NewExtensibleConstrainedInteger(test.param.lb, test.param.ub, test.param.allign)
+		_, shift, err = val.Decode(test.encoded, 0)
+		if err != nil {
+			t.Fatalf("%s error decode extensible integer %v", test.name, err)
+		}
+		if val.Value != test.value || shift != test.shift {
+			t.Errorf("%s result is not expected \n want %v, \n got  %v", test.name, test.value, val.Value)
+		}
+	}
+}
+
+func TestSemiConstrainedAndUnconstrainedInteger(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+	}
+	for _, test := range []struct {
+		name       string
+		integer    Codec
+		value      int
+		input      []byte
+		inputShift uint8
+		want       result
+	}{
+		{
+			name:    `Semi_Zero`,
+			integer: NewSemiConstrainedInteger(0, true),
+			value:   0,
+			want:    result{data: []byte{0x01, 0x00}},
+		},
+		{
+			name:    `Semi_Two_Octets`,
+			integer: NewSemiConstrainedInteger(0, true),
+			value:   256,
+			want:    result{data: []byte{0x02, 0x01, 0x00}},
+		},
+		{
+			name:    `Semi_Negative_Lower_Band`,
+			integer: NewSemiConstrainedInteger(-5, true),
+			value:   5,
+			want:    result{data: []byte{0x01, 0x0a}},
+		},
+		{
+			name:    `Semi_Max_Negative_Lower_Band`,
+			integer: NewSemiConstrainedInteger(-5, true),
+			value:   math.MaxInt64,
+			want:    result{data: []byte{0x08, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04}},
+		},
+		{
+			name:    `Semi_Min_Lower_Band`,
+			integer: NewSemiConstrainedInteger(math.MinInt64, true),
+			value:   0,
+			want:    result{data: []byte{0x08, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		},
+		{
+			name:    `Semi_Min_Lower_Band_Unaligned`,
+			integer: NewSemiConstrainedInteger(math.MinInt64, false),
+			value:   math.MaxInt64,
+			want:    result{data: []byte{0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		},
+		{
+			name:       `Semi_Alligned_Shift`,
+			integer:    NewSemiConstrainedInteger(0, true),
+			value:      1,
+			input:      []byte{0xe0},
+			inputShift: 3,
+			want:       result{data: []byte{0xe0, 0x01, 0x01}},
+		},
+		{
+			name:       `Semi_Unaligned_Shift`,
+			integer:    NewSemiConstrainedInteger(0, false),
+			value:      1,
+			input:      []byte{0xe0},
+			inputShift: 3,
+			want:       result{data: []byte{0xe0, 0x20, 0x20}, shift: 3},
+		},
+		{
+			name:    `Unconstrained_Positive`,
+			integer: NewUnconstrainedInteger(true),
+			value:   127,
+			want:    result{data: []byte{0x01, 0x7f}},
+		},
+		{
+			name:    `Unconstrained_Sign_Octet`,
+			integer: NewUnconstrainedInteger(true),
+			value:   128,
+			want:    result{data: []byte{0x02, 0x00, 0x80}},
+		},
+		{
+			name:    `Unconstrained_Negative`,
+			integer: NewUnconstrainedInteger(false),
+			value:   -129,
+			want:    result{data: []byte{0x02, 0xff, 0x7f}},
+		},
+	} {
+		switch v := test.integer.(type) {
+		case *SemiConstrainedInteger:
+			v.Value = test.value
+		case *UnconstrainedInteger:
+			v.Value = test.value
+		}
+		res := result{}
+		var err error
+		res.data, res.shift, err = test.integer.Encode(test.input, test.inputShift)
+		if err != nil {
+			t.Fatalf("%s error encode integer %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s encoding is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+		var value int
+		_, _, err = test.integer.Decode(res.data, test.inputShift)
+		switch v := test.integer.(type) {
+		case *SemiConstrainedInteger:
+			value = v.Value
+		case *UnconstrainedInteger:
+			value = v.Value
+		}
+		if err != nil || value != test.value {
+			t.Errorf("%s result is not expected \n want %v, \n got  %v (%v)", test.name, test.value, value, err)
+		}
+	}
+}
+
+func TestIntegerConstrainLowerBand(t *testing.T) {
+	type result struct {
+		value int
+		err   error
+	}
+	type intParam struct {
+		lb        int
+		ub        int
+		allign    bool
+		rawOffset bool
+	}
+	for _, test := range []struct {
+		name  string
+		param intParam
+		input []byte
+		want  result
+	}{
+		{
+			name:  `Negative_Lower_Band`,
+			param: intParam{lb: -10, ub: 10, allign: true},
+			input: []byte{0x00},
+			want:  result{value: -10},
+		},
+		{
+			name:  `Negative_Value`,
+			param: intParam{lb: -10, ub: 10, allign: true},
+			input: []byte{0x18},
+			want:  result{value: -7},
+		},
+		{
+			name:  `One_Octet_Lower_Band`,
+			param: intParam{lb: 1, ub: 256, allign: true},
+			input: []byte{0xff},
+			want:  result{value: 256},
+		},
+		{
+			name:  `Raw_Offset`,
+			param: intParam{lb: 1, ub: 256, allign: true, rawOffset: true},
+			input: []byte{0xff},
+			want:  result{value: 255},
+		},
+		{
+			name:  `Unaligned_Constraint_Violation`,
+			param: intParam{lb: 0, ub: 5, allign: false},
+			input: []byte{0xe0},
+			want:  result{err: ErrorConstraintViolation},
+		},
+		{
+			name:  `Aligned_Constraint_Violation`,
+			param: intParam{lb: 0, ub: 299, allign: true},
+			input: []byte{0x01, 0x2c},
+			want:  result{err: ErrorConstraintViolation},
+		},
+	} {
+		res := result{}
+		val := NewConstrainedInteger(test.param.lb, test.param.ub, test.param.allign)
+		val.RawOffset = test.param.rawOffset
+		_, _, res.err = val.Decode(test.input, 0)
+		if res.err == nil {
+			res.value = val.Value
+		}
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+			continue
+		}
+		if res.err != nil {
+			continue
+		}
+		encoded, _, err := val.Encode(nil, 0)
+		if err != nil || !reflect.DeepEqual(encoded, test.input) {
+			t.Errorf("%s encoding is not expected \n want %v, \n got  %v (%v)", test.name, test.input, encoded, err)
+		}
+	}
+}
+
+// full-width 64-bit ranges
+func TestIntegerConstrainFullRange(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		integer Codec
+		value   interface{}
+		encoded []byte
+	}{
+		{
+			name:    `Int64_Min`,
+			integer: NewConstrainedInteger(math.MinInt64, math.MaxInt64, true),
+			value:   math.MinInt64,
+			encoded: []byte{0x00, 0x00},
+		},
+		{
+			name:    `Int64_Max`,
+			integer: NewConstrainedInteger(math.MinInt64, math.MaxInt64, true),
+			value:   math.MaxInt64,
+			encoded: []byte{0xe0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name:    `Int64_Minus_One`,
+			integer: NewConstrainedInteger(math.MinInt64, math.MaxInt64, true),
+			value:   -1,
+			encoded: []byte{0xe0, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name:    `Int64_Unaligned`,
+			integer: NewConstrainedInteger(math.MinInt64, math.MaxInt64, false),
+			value:   0,
+			encoded: []byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:    `Uint64_Max`,
+			integer: NewUnsignedConstrainedInteger(0, math.MaxUint64, true),
+			value:   uint64(math.MaxUint64),
+			encoded: []byte{0xe0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name:    `Uint64_Upper_Half`,
+			integer: NewUnsignedConstrainedInteger(1<<63, math.MaxUint64, true),
+			value:   uint64(1<<63 + 258),
+			encoded: []byte{0x20, 0x01, 0x02},
+		},
+		{
+			name:    `Uint64_Unaligned`,
+			integer: NewUnsignedConstrainedInteger(0, math.MaxUint64, false),
+			value:   uint64(5),
+			encoded: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+		},
+	} {
+		switch v := test.integer.(type) {
+		case *ConstrainedInteger:
+			v.Value = test.value.(int)
+		case *UnsignedConstrainedInteger:
+			v.Value = test.value.(uint64)
+		}
+		encoded, _, err := test.integer.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode integer %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.encoded, encoded) {
+			t.Errorf("%s encoding is not expected \n want %x, \n got  %x", test.name, test.encoded, encoded)
+		}
+		var value interface{}
+		_, _, err = test.integer.Decode(test.encoded, 0)
+		switch v := test.integer.(type) {
+		case *ConstrainedInteger:
+			value = v.Value
+		case *UnsignedConstrainedInteger:
+			value = v.Value
+		}
+		if err != nil || value != test.value {
+			t.Errorf("%s result is not expected \n want %v, \n got  %v (%v)", test.name, test.value, value, err)
+		}
+	}
+}
+
+// aligned indefinite length case, ranges above 64K
+func TestIntegerConstrainIndefiniteLength(t *testing.T) {
+	type result struct {
+		value int
+		data  []byte
+		shift uint8
+		err   error
+	}
+	for _, test := range []struct {
+		name    string
+		ub      int
+		value   int
+		input   []byte
+		shift   uint8
+		encoded []byte
+		want    result
+	}{
+		{
+			name:    `Uint32_Zero`,
+			ub:      4294967295,
+			value:   0,
+			encoded: []byte{0x00, 0x00},
+		},
+		{
+			name:    `Uint32_Two_Octets`,
+			ub:      4294967295,
+			value:   256,
+			encoded: []byte{0x40, 0x01, 0x00},
+		},
+		{
+			name:    `Uint32_Max`,
+			ub:      4294967295,
+			value:   4294967295,
+			encoded: []byte{0xc0, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name:    `Uint32_Shift`,
+			ub:      4294967295,
+			value:   256,
+			input:   []byte{0xe0},
+			shift:   3,
+			encoded: []byte{0xe8, 0x01, 0x00},
+		},
+		{
+			name:    `Uint32_Shift_Length_On_Corner`,
+			ub:      4294967295,
+			value:   65536,
+			input:   []byte{0xfc},
+			shift:   6,
+			encoded: []byte{0xfe, 0x01, 0x00, 0x00},
+		},
+		{
+			name:    `Pow40_Five_Octets`,
+			ub:      1 << 40,
+			value:   1<<32 + 1,
+			encoded: []byte{0x80, 0x01, 0x00, 0x00, 0x00, 0x01},
+		},
+		{
+			name:    `Pow40_Max`,
+			ub:      1 << 40,
+			value:   1 << 40,
+			encoded: []byte{0xa0, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:    `Pow40_Shift`,
+			ub:      1 << 40,
+			value:   1,
+			input:   []byte{0x80},
+			shift:   1,
+			encoded: []byte{0x80, 0x01},
+		},
+	} {
+		val := NewConstrainedInteger(0, test.ub, true)
+		val.Value = test.value
+		data, shift, err := val.Encode(test.input, test.shift)
+		if err != nil || !reflect.DeepEqual(test.encoded, data) || shift != 0 {
+			t.Logf("%s encoding is not expected \n want %x, \n got  %x %d (%v)", test.name, test.encoded, data, shift, err)
+			t.Fail()
+		}
+		res := result{}
+		val = NewConstrainedInteger(0, test.ub, true)
+		res.data, res.shift, res.err = val.Decode(test.encoded, test.shift)
+		res.value = val.Value
+		want := result{value: test.value, data: []byte{}}
+		if !reflect.DeepEqual(want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, want, res)
+			t.Fail()
+		}
+	}
+
+	for _, test := range []struct {
+		name  string
+		ub    int
+		input []byte
+		err   error
+	}{
+		{
+			name:  `Length_Above_Range_Octets`,
+			ub:    1 << 40,
+			input: []byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+			err:   ErrorIncorrectDecode,
+		},
+		{
+			name:  `Value_Above_Upper_Band`,
+			ub:    1 << 40,
+			input: []byte{0xa0, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01},
+			err:   ErrorConstraintViolation,
+		},
+		{
+			name:  `Value_Truncated`,
+			ub:    4294967295,
+			input: []byte{0xc0, 0xff, 0xff},
+			err:   ErrorBufferToShort,
+		},
+	} {
+		val := NewConstrainedInteger(0, test.ub, true)
+		if _, _, err := val.Decode(test.input, 0); err != test.err {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.err, err)
+			t.Fail()
+		}
+	}
+}
+
+// semi-constrained value below lower band
+func TestSemiConstrainedIntegerConstraintViolation(t *testing.T) {
+	integer := NewSemiConstrainedInteger(10, true)
+	integer.Value = 9
+	if _, _, err := integer.Encode(nil, 0); err != ErrorConstraintViolation {
+		t.Errorf("result is not expected \n want %v, \n got  %v", ErrorConstraintViolation, err)
+	}
+}
@@ -0,0 +1,30 @@
+package asn1_per
+
+// NULL Type (X.691 p.18), encoded as zero bits
+
+type Null struct{}
+
+func NewNull() *Null {
+	return &Null{}
+}
+
+func (n *Null) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	return data, shift, nil
+}
+
+func (n *Null) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	return data, shift, nil
+}
+
+// Decode from current position of BitReader
+func (n *Null) DecodeFrom(r *BitReader) error {
+	return r.decode(n.Decode)
+}
+
+// Encode to current position of BitWriter
+func (n *Null) EncodeTo(w *BitWriter) error {
+	return w.encode(n.Encode)
+}
@@ -0,0 +1,22 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNull(t *testing.T) {
+	n := NewNull()
+	data, shift, err := n.Decode([]byte{0xab}, 5)
+	if err != nil || !reflect.DeepEqual(data, []byte{0xab}) || shift != 5 {
+		t.Errorf("null decode is not expected: %v %d %v", data, shift, err)
+	}
+	data, shift, err = n.Decode(nil, 0)
+	if err != nil || len(data) != 0 || shift != 0 {
+		t.Errorf("null decode of empty data is not expected: %v %d %v", data, shift, err)
+	}
+	data, shift, err = n.Encode([]byte{0xa8}, 5)
+	if err != nil || !reflect.DeepEqual(data, []byte{0xa8}) || shift != 5 {
+		t.Errorf("null encode is not expected: %v %d %v", data, shift, err)
+	}
+}
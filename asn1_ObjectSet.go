@@ -0,0 +1,32 @@
+package asn1_per
+
+// Information object sets (X.681): type of open type value depends on object set and id
+
+type ObjectRegistry struct {
+	sets map[string]map[int]func() Codec
+}
+
+func NewObjectRegistry() *ObjectRegistry {
+	return &ObjectRegistry{
+		sets: make(map[string]map[int]func() Codec),
+	}
+}
+
+// Register codec factory for id in object set (previous factory for id is replaced)
+func (r *ObjectRegistry) Register(set string, id int, newValue func() Codec) {
+	objects, ok := r.sets[set]
+	if !ok {
+		objects = make(map[int]func() Codec)
+		r.sets[set] = objects
+	}
+	objects[id] = newValue
+}
+
+// Return codec factory for id in object set, ok = false if id is unknown
+func (r *ObjectRegistry) Lookup(set string, id int) (newValue func() Codec, ok bool) {
+	if r == nil {
+		return nil, false
+	}
+	newValue, ok = r.sets[set][id]
+	return
+}
@@ -0,0 +1,30 @@
+package asn1_per
+
+import (
+	"testing"
+)
+
+func TestObjectRegistry(t *testing.T) {
+	objects := NewObjectRegistry()
+	objects.Register(`InitialUEMessage-IEs`, 8, func() Codec { return NewConstrainedInteger(0, 16777215, true) })
+	objects.Register(`InitialUEMessage-IEs`, 26, func() Codec { return NewUnconstrainedOctetString(true) })
+
+	newValue, ok := objects.Lookup(`InitialUEMessage-IEs`, 26)
+	if !ok {
+		t.Fatalf("id 26 is not found")
+	}
+	if _, ok = newValue().(*UnconstrainedOctetString); !ok {
+		t.Errorf("codec of id 26 is not expected")
+	}
+	// id is looked up in its object set only
+	if _, ok = objects.Lookup(`UplinkNASTransport-IEs`, 26); ok {
+		t.Errorf("id 26 is found in other object set")
+	}
+	if _, ok = objects.Lookup(`InitialUEMessage-IEs`, 0); ok {
+		t.Errorf("unknown id 0 is found")
+	}
+	var empty *ObjectRegistry
+	if _, ok = empty.Lookup(`InitialUEMessage-IEs`, 8); ok {
+		t.Errorf("id is found in nil registry")
+	}
+}
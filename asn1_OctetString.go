@@ -1,11 +1,16 @@
 package asn1_per
 
+import (
+	"math"
+)
+
 // OCTET STRING with fixed length
 
 type FixedOctetString struct {
-	Size     int
-	Alligned bool
-	Value    []byte
+	Size       int
+	Alligned   bool
+	Extensible bool // SIZE (size, ...), Value of other length is encoded with length determinant
+	Value      []byte
 }
 
 func NewFixedOctetString(size int, alligned bool) *FixedOctetString {
@@ -16,6 +21,13 @@ func NewFixedOctetString(size int, alligned bool) *FixedOctetString {
 	}
 }
 
+// OCTET STRING with extensible fixed length SIZE (size, ...)
+func NewExtensibleFixedOctetString(size int, alligned bool) *FixedOctetString {
+	o := NewFixedOctetString(size, alligned)
+	o.Extensible = true
+	return o
+}
+
 func (o *FixedOctetString) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
 	if len(data) == 0 {
 		err = ErrorBufferToShort
@@ -24,16 +36,65 @@ func (o *FixedOctetString) Decode(data []byte, shift uint8) (outData []byte, out
 	if len(o.Value) != 0 {
 		o.Value = o.Value[:0]
 	}
+	if o.Extensible {
+		var extended uint64
+		extended, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+		// length outside of root
+		if extended == 1 {
+			return octetStringWithLengthDecode(data, shift, 0, math.MaxInt, o.Alligned, &o.Value)
+		}
+	}
+	// 64K octets and more, length determinant is encoded (X.691 p.17.8)
+	if o.Size >= 65536 {
+		return octetStringWithLengthDecode(data, shift, o.Size, o.Size, o.Alligned, &o.Value)
+	}
 	return fixedOctetStringDecode(data, shift, o.Size, o.Alligned, &o.Value)
 }
 
+// Encode Value (Value length must be Size octets, if not Extensible)
+func (o *FixedOctetString) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if o.Extensible {
+		outside := len(o.Value) != o.Size
+		data, shift = putBits(data, shift, boolToBit(outside), 1)
+		// length outside of root
+		if outside {
+			return openTypeEncode(data, shift, o.Value, o.Alligned)
+		}
+	}
+	if len(o.Value) != o.Size {
+		err = ErrorIncorrectLength
+		return
+	}
+	if o.Size >= 65536 {
+		return openTypeEncode(data, shift, o.Value, o.Alligned)
+	}
+	return fixedOctetStringEncode(data, shift, o.Size, o.Alligned, o.Value)
+}
+
+// Decode from current position of BitReader
+func (o *FixedOctetString) DecodeFrom(r *BitReader) error {
+	return r.decode(o.Decode)
+}
+
+// Encode to current position of BitWriter
+func (o *FixedOctetString) EncodeTo(w *BitWriter) error {
+	return w.encode(o.Encode)
+}
+
 // OCTET STRING with constrained length
 
 type ConstrainedOctetString struct {
-	LowerBand int
-	UpperBand int
-	Alligned  bool
-	Value     []byte
+	LowerBand  int
+	UpperBand  int
+	Alligned   bool
+	Extensible bool // SIZE (lb..ub, ...), Value of length outside of root is encoded with length determinant
+	Value      []byte
 }
 
 func NewConstrainedOctetString(lb int, ub int, alligned bool) *ConstrainedOctetString {
@@ -49,6 +110,13 @@ func NewConstrainedOctetString(lb int, ub int, alligned bool) *ConstrainedOctetS
 	}
 }
 
+// OCTET STRING with extensible constrained length SIZE (lb..ub, ...)
+func NewExtensibleConstrainedOctetString(lb int, ub int, alligned bool) *ConstrainedOctetString {
+	o := NewConstrainedOctetString(lb, ub, alligned)
+	o.Extensible = true
+	return o
+}
+
 func (o *ConstrainedOctetString) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
 	if o.UpperBand < o.LowerBand {
 		err = ErrorInputParameters
@@ -61,20 +129,187 @@ func (o *ConstrainedOctetString) Decode(data []byte, shift uint8) (outData []byt
 	if len(o.Value) != 0 {
 		o.Value = o.Value[:0]
 	}
+	if o.Extensible {
+		var extended uint64
+		extended, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+		// length outside of root
+		if extended == 1 {
+			return octetStringWithLengthDecode(data, shift, 0, math.MaxInt, o.Alligned, &o.Value)
+		}
+	}
+	// ub 64K and more, length determinant is encoded (X.691 p.17.8)
+	if o.UpperBand >= 65536 {
+		return octetStringWithLengthDecode(data, shift, o.LowerBand, o.UpperBand, o.Alligned, &o.Value)
+	}
 	if o.UpperBand == o.LowerBand {
 		return fixedOctetStringDecode(data, shift, o.UpperBand, o.Alligned, &o.Value)
 	}
 	var size int
-	rang := o.UpperBand - o.LowerBand + 1
-
-	size, data, shift, err = constrainedWholeNumber(data, shift, rang, o.Alligned)
+	size, data, shift, err = DecodeConstrainedLength(data, shift, o.LowerBand, o.UpperBand, o.Alligned)
 	if err != nil {
 		return
 	}
-	size += o.LowerBand
 	return fixedOctetStringDecode(data, shift, size, o.Alligned, &o.Value)
 }
 
+// Encode Value (Value length must be in range LowerBand..UpperBand)
+func (o *ConstrainedOctetString) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if o.UpperBand < o.LowerBand {
+		err = ErrorInputParameters
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	size := len(o.Value)
+	if o.Extensible {
+		outside := size < o.LowerBand || size > o.UpperBand
+		data, shift = putBits(data, shift, boolToBit(outside), 1)
+		// length outside of root
+		if outside {
+			return openTypeEncode(data, shift, o.Value, o.Alligned)
+		}
+	}
+	if size < o.LowerBand || size > o.UpperBand {
+		err = ErrorIncorrectLength
+		return
+	}
+	if o.UpperBand >= 65536 {
+		return openTypeEncode(data, shift, o.Value, o.Alligned)
+	}
+	if o.UpperBand == o.LowerBand {
+		return fixedOctetStringEncode(data, shift, size, o.Alligned, o.Value)
+	}
+	data, shift, err = EncodeConstrainedLength(data, shift, size, o.LowerBand, o.UpperBand, o.Alligned)
+	if err != nil {
+		return
+	}
+	return fixedOctetStringEncode(data, shift, size, o.Alligned, o.Value)
+}
+
+// Decode from current position of BitReader
+func (o *ConstrainedOctetString) DecodeFrom(r *BitReader) error {
+	return r.decode(o.Decode)
+}
+
+// Encode to current position of BitWriter
+func (o *ConstrainedOctetString) EncodeTo(w *BitWriter) error {
+	return w.encode(o.Encode)
+}
+
+// OCTET STRING with unconstrained length
+
+type UnconstrainedOctetString struct {
+	Alligned bool
+	Value    []byte
+}
+
+func NewUnconstrainedOctetString(alligned bool) *UnconstrainedOctetString {
+	return &UnconstrainedOctetString{
+		Alligned: alligned,
+	}
+}
+
+func (o *UnconstrainedOctetString) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	o.Value = o.Value[:0]
+	return octetStringWithLengthDecode(data, shift, 0, math.MaxInt, o.Alligned, &o.Value)
+}
+
+// Encode Value, 16K octets and more are encoded in fragments
+func (o *UnconstrainedOctetString) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	return openTypeEncode(data, shift, o.Value, o.Alligned)
+}
+
+// Decode from current position of BitReader
+func (o *UnconstrainedOctetString) DecodeFrom(r *BitReader) error {
+	return r.decode(o.Decode)
+}
+
+// Encode to current position of BitWriter
+func (o *UnconstrainedOctetString) EncodeTo(w *BitWriter) error {
+	return w.encode(o.Encode)
+}
+
+// OCTET STRING with semi-constrained length SIZE (lb..MAX)
+
+type SemiConstrainedOctetString struct {
+	LowerBand int
+	Alligned  bool
+	Value     []byte
+}
+
+func NewSemiConstrainedOctetString(lb int, alligned bool) *SemiConstrainedOctetString {
+	return &SemiConstrainedOctetString{
+		LowerBand: lb,
+		Alligned:  alligned,
+	}
+}
+
+// Decode Value, length is encoded as unconstrained length determinant (X.691 p.11.9.4.2)
+func (o *SemiConstrainedOctetString) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if o.LowerBand < 0 {
+		err = ErrorInputParameters
+		return
+	}
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	o.Value = o.Value[:0]
+	return octetStringWithLengthDecode(data, shift, o.LowerBand, math.MaxInt, o.Alligned, &o.Value)
+}
+
+// Encode Value (Value length must be LowerBand or more)
+func (o *SemiConstrainedOctetString) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if o.LowerBand < 0 {
+		err = ErrorInputParameters
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if len(o.Value) < o.LowerBand {
+		err = ErrorIncorrectLength
+		return
+	}
+	return openTypeEncode(data, shift, o.Value, o.Alligned)
+}
+
+// Decode from current position of BitReader
+func (o *SemiConstrainedOctetString) DecodeFrom(r *BitReader) error {
+	return r.decode(o.Decode)
+}
+
+// Encode to current position of BitWriter
+func (o *SemiConstrainedOctetString) EncodeTo(w *BitWriter) error {
+	return w.encode(o.Encode)
+}
+
+// Decode octet string with length determinant, length must be in range lb..ub
+func octetStringWithLengthDecode(data []byte, shift uint8, lb int, ub int, alligned bool, value *[]byte) (outData []byte, outShift uint8, err error) {
+	var octets []byte
+	octets, outData, outShift, err = openTypeDecode(data, shift, alligned)
+	if err != nil {
+		return
+	}
+	if len(octets) < lb || len(octets) > ub {
+		err = ErrorIncorrectLength
+		return
+	}
+	*value = append(*value, octets...)
+	return
+}
+
 // Decode octed string wiht fixed length
 func fixedOctetStringDecode(data []byte, shift uint8, size int, alligned bool, value *[]byte) (outData []byte, outShift uint8, err error) {
 	if size > 2 && alligned {
@@ -83,7 +318,7 @@ func fixedOctetStringDecode(data []byte, shift uint8, size int, alligned bool, v
 			shift = 0
 		}
 	}
-	if len(data) < size {
+	if len(data) < size || (shift != 0 && len(data) < size+1) {
 		err = ErrorBufferToShort
 		return
 	}
@@ -100,3 +335,12 @@ func fixedOctetStringDecode(data []byte, shift uint8, size int, alligned bool, v
 	outData = data[size:]
 	return
 }
+
+// Encode octet string with fixed length, return data with appended value and bit shift for next encoding
+func fixedOctetStringEncode(data []byte, shift uint8, size int, alligned bool, value []byte) (outData []byte, outShift uint8, err error) {
+	if size > 2 && alligned {
+		shift = 0
+	}
+	outData, outShift = putBitString(data, shift, value, size*8)
+	return
+}
@@ -1,6 +1,7 @@
 package asn1_per
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 )
@@ -117,3 +118,381 @@ func TestConstrainedOctetString(t *testing.T) {
 		}
 	}
 }
+
+func TestOctetStringEncode(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+	for _, test := range []struct {
+		name  string
+		octet interface {
+			Encode(data []byte, shift uint8) ([]byte, uint8, error)
+		}
+		input []byte
+		shift uint8
+		want  result
+	}{
+		{
+			name:  "Fixed",
+			octet: &FixedOctetString{Size: 2, Alligned: true, Value: []byte{0x18, 0x00}},
+			input: []byte{0x50},
+			shift: 4,
+			want: result{
+				data:  []byte{0x51, 0x80, 0x00},
+				shift: 4,
+			},
+		},
+		{
+			name:  "Fixed_Alligned",
+			octet: &FixedOctetString{Size: 3, Alligned: true, Value: []byte{0x01, 0x02, 0x03}},
+			input: []byte{0x50},
+			shift: 4,
+			want: result{
+				data:  []byte{0x50, 0x01, 0x02, 0x03},
+				shift: 0,
+			},
+		},
+		{
+			name:  "Fixed_Incorrect_Length",
+			octet: &FixedOctetString{Size: 3, Alligned: true, Value: []byte{0x01, 0x02}},
+			input: []byte{0x50},
+			shift: 4,
+			want: result{
+				err: ErrorIncorrectLength,
+			},
+		},
+		{
+			name:  "Constrained",
+			octet: &ConstrainedOctetString{LowerBand: 3, UpperBand: 8, Alligned: true, Value: []byte{0xaf, 0x20, 0x60, 0x52, 0xf0}},
+			input: []byte{0x00},
+			shift: 2,
+			want: result{
+				data:  []byte{0x10, 0xaf, 0x20, 0x60, 0x52, 0xf0},
+				shift: 0,
+			},
+		},
+		{
+			name:  "Constrained_Unaligned",
+			octet: &ConstrainedOctetString{LowerBand: 3, UpperBand: 8, Alligned: false, Value: []byte{0xaf, 0x20, 0x60}},
+			input: nil,
+			shift: 0,
+			want: result{
+				data:  []byte{0x15, 0xe4, 0x0c, 0x00},
+				shift: 3,
+			},
+		},
+		{
+			name:  "Constrained_Incorrect_Length",
+			octet: &ConstrainedOctetString{LowerBand: 3, UpperBand: 8, Alligned: true, Value: []byte{0xaf, 0x20}},
+			input: nil,
+			shift: 0,
+			want: result{
+				err: ErrorIncorrectLength,
+			},
+		},
+	} {
+		res := result{}
+		res.data, res.shift, res.err = test.octet.Encode(test.input, test.shift)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+	}
+}
+
+// decode -> encode must be lossless for any size and shift
+func TestOctetStringRoundTrip(t *testing.T) {
+	for _, alligned := range []bool{true, false} {
+		for size := 0; size <= 10; size++ {
+			for shift := uint8(0); shift < 8; shift++ {
+				var input []byte
+				if shift != 0 {
+					input = []byte{0xff << (8 - shift)}
+				}
+				value := make([]byte, size)
+				for i := range value {
+					value[i] = byte(0xa5 + 37*i)
+				}
+				o := NewConstrainedOctetString(0, 10, alligned)
+				o.Value = value
+				encoded, outShift, err := o.Encode(input, shift)
+				if err != nil {
+					t.Fatalf("size %d shift %d: error encode %v", size, shift, err)
+				}
+				d := NewConstrainedOctetString(0, 10, alligned)
+				_, decShift, err := d.Decode(encoded, shift)
+				if err != nil {
+					t.Fatalf("size %d shift %d alligned %v: error decode %v", size, shift, alligned, err)
+				}
+				if !reflect.DeepEqual(value, d.Value) || decShift != outShift {
+					t.Errorf("size %d shift %d alligned %v: want %x, got %x", size, shift, alligned, value, d.Value)
+				}
+			}
+		}
+	}
+}
+
+// OCTET STRING without SIZE and with SIZE (lb..MAX)
+func TestUnconstrainedOctetString(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+	for _, test := range []struct {
+		name    string
+		octet   Codec
+		value   []byte
+		input   []byte
+		shift   uint8
+		encoded result
+	}{
+		{
+			name:    `Aligned`,
+			octet:   NewUnconstrainedOctetString(true),
+			value:   []byte{0x01, 0x02},
+			input:   []byte{0xe0},
+			shift:   3,
+			encoded: result{data: []byte{0xe0, 0x02, 0x01, 0x02}},
+		},
+		{
+			name:    `Unaligned`,
+			octet:   NewUnconstrainedOctetString(false),
+			value:   []byte{0x01, 0x02},
+			input:   []byte{0xe0},
+			shift:   3,
+			encoded: result{data: []byte{0xe0, 0x40, 0x20, 0x40}, shift: 3},
+		},
+		{
+			name:    `Empty`,
+			octet:   NewUnconstrainedOctetString(true),
+			value:   []byte{},
+			encoded: result{data: []byte{0x00}},
+		},
+		{
+			name:    `Two_Octets_Length`,
+			octet:   NewUnconstrainedOctetString(true),
+			value:   make([]byte, 200),
+			encoded: result{data: append([]byte{0x80, 0xc8}, make([]byte, 200)...)},
+		},
+		{
+			name:    `Semi_Constrained`,
+			octet:   NewSemiConstrainedOctetString(1, true),
+			value:   []byte{0x7e, 0x00, 0x41},
+			encoded: result{data: []byte{0x03, 0x7e, 0x00, 0x41}},
+		},
+		{
+			name:    `Semi_Constrained_Too_Short`,
+			octet:   NewSemiConstrainedOctetString(4, true),
+			value:   []byte{0x7e, 0x00, 0x41},
+			encoded: result{err: ErrorIncorrectLength},
+		},
+	} {
+		var decoded []byte
+		switch o := test.octet.(type) {
+		case *UnconstrainedOctetString:
+			o.Value = test.value
+		case *SemiConstrainedOctetString:
+			o.Value = test.value
+		}
+		res := result{}
+		res.data, res.shift, res.err = test.octet.Encode(test.input, test.shift)
+		if !reflect.DeepEqual(test.encoded, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.encoded, res)
+			t.Fail()
+		}
+		if res.err != nil {
+			continue
+		}
+		switch o := test.octet.(type) {
+		case *UnconstrainedOctetString:
+			o.Value = nil
+			_, _, res.err = o.Decode(res.data, test.shift)
+			decoded = o.Value
+		case *SemiConstrainedOctetString:
+			o.Value = nil
+			_, _, res.err = o.Decode(res.data, test.shift)
+			decoded = o.Value
+		}
+		if res.err != nil || len(decoded) != len(test.value) || len(decoded) != 0 && !reflect.DeepEqual(test.value, decoded) {
+			t.Logf("%s decoding is not expected \n want %x, \n got  %x (%v)", test.name, test.value, decoded, res.err)
+			t.Fail()
+		}
+	}
+
+	// length of decoded value is checked against lower band
+	o := NewSemiConstrainedOctetString(4, false)
+	if _, _, err := o.Decode([]byte{0x01, 0xff}, 0); err != ErrorIncorrectLength {
+		t.Errorf("Semi_Constrained_Decode_Too_Short result is not expected \n want %v, \n got  %v", ErrorIncorrectLength, err)
+	}
+}
+
+// X.691 p.11.9.3.8: fragments of 64K, 48K, 32K and 16K octets
+func TestUnconstrainedOctetStringFragmentation(t *testing.T) {
+	for _, test := range []struct {
+		size    int
+		headers []int // positions and values of length determinants in aligned encoding
+	}{
+		{size: 16383, headers: []int{0, 0xbf}},
+		{size: 16384, headers: []int{0, 0xc1, 16385, 0x00}},
+		{size: 65536 + 1, headers: []int{0, 0xc4, 65537, 0x01}},
+		{size: 100000, headers: []int{0, 0xc4, 65537, 0xc2, 65537 + 32769, 0x86}},
+	} {
+		for _, alligned := range []bool{true, false} {
+			value := make([]byte, test.size)
+			for i := range value {
+				value[i] = byte(0xa5 + 37*i)
+			}
+			o := NewUnconstrainedOctetString(alligned)
+			o.Value = value
+			encoded, shift, err := o.Encode(nil, 0)
+			if err != nil {
+				t.Fatalf("size %d: error encode %v", test.size, err)
+			}
+			for i := 0; i < len(test.headers); i += 2 {
+				if encoded[test.headers[i]] != byte(test.headers[i+1]) {
+					t.Errorf("size %d: length determinant at %d is %x", test.size, test.headers[i], encoded[test.headers[i]])
+				}
+			}
+			d := NewUnconstrainedOctetString(alligned)
+			out, outShift, err := d.Decode(encoded, 0)
+			if err != nil {
+				t.Fatalf("size %d alligned %v: error decode %v", test.size, alligned, err)
+			}
+			if !reflect.DeepEqual(value, d.Value) || outShift != shift || len(out) != 0 {
+				t.Errorf("size %d alligned %v: decoded value is not expected", test.size, alligned)
+			}
+		}
+	}
+}
+
+// sizes at 16K and 64K boundaries, length determinant with fragmentation for ub >= 64K
+func TestLargeOctetString(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		octet  Codec
+		size   int
+		header []byte // first octets of encoding
+		length int    // length of encoding
+	}{
+		{name: `Fixed_16K`, octet: NewFixedOctetString(16384, true), size: 16384, length: 16384},
+		{name: `Fixed_64K_Minus_1`, octet: NewFixedOctetString(65535, true), size: 65535, length: 65535},
+		{name: `Fixed_64K`, octet: NewFixedOctetString(65536, true), size: 65536, header: []byte{0xc4}, length: 65538},
+		{name: `Fixed_64K_Unaligned`, octet: NewFixedOctetString(65536, false), size: 65536, header: []byte{0xc4}, length: 65538},
+		{name: `Constrained_16K`, octet: NewConstrainedOctetString(0, 65535, true), size: 16384, header: []byte{0x40, 0x00}, length: 16386},
+		{name: `Constrained_64K_16K`, octet: NewConstrainedOctetString(0, 65536, true), size: 16384, header: []byte{0xc1}, length: 16386},
+		{name: `Constrained_64K_16K_Minus_1`, octet: NewConstrainedOctetString(0, 65536, true), size: 16383, header: []byte{0xbf, 0xff}, length: 16385},
+		{name: `Constrained_64K_Max`, octet: NewConstrainedOctetString(1, 70000, false), size: 70000, header: []byte{0xc4}, length: 70003},
+	} {
+		value := make([]byte, test.size)
+		for i := range value {
+			value[i] = byte(0xa5 + 37*i)
+		}
+		var decoded *[]byte
+		switch o := test.octet.(type) {
+		case *FixedOctetString:
+			o.Value = value
+			decoded = &o.Value
+		case *ConstrainedOctetString:
+			o.Value = value
+			decoded = &o.Value
+		}
+		encoded, shift, err := test.octet.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode %v", test.name, err)
+		}
+		if len(encoded) != test.length || shift != 0 || !bytes.HasPrefix(encoded, test.header) {
+			t.Errorf("%s encoding is not expected: length %d, header %x", test.name, len(encoded), encoded[:2])
+		}
+		*decoded = nil
+		out, _, err := test.octet.Decode(encoded, 0)
+		if err != nil || len(out) != 0 || !reflect.DeepEqual(value, *decoded) {
+			t.Errorf("%s decoding is not expected (%v)", test.name, err)
+		}
+	}
+
+	// decoded length must match the size constraint
+	encoded, _, _ := NewUnconstrainedOctetString(true).Encode(nil, 0)
+	if _, _, err := NewFixedOctetString(65536, true).Decode(encoded, 0); err != ErrorIncorrectLength {
+		t.Errorf("Fixed_64K_Incorrect_Length result is not expected \n want %v, \n got  %v", ErrorIncorrectLength, err)
+	}
+}
+
+// SIZE constraint with extension marker
+func TestExtensibleOctetString(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+	for _, test := range []struct {
+		name  string
+		octet Codec
+		value []byte
+		want  result
+	}{
+		{
+			name:  `Constrained_Root`,
+			octet: NewExtensibleConstrainedOctetString(1, 8, true),
+			value: []byte{0x11, 0x22},
+			want:  result{data: []byte{0x11, 0x12, 0x20}, shift: 4},
+		},
+		{
+			name:  `Constrained_Extension`,
+			octet: NewExtensibleConstrainedOctetString(1, 8, true),
+			value: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09},
+			want:  result{data: []byte{0x80, 0x09, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09}},
+		},
+		{
+			name:  `Constrained_Extension_Empty`,
+			octet: NewExtensibleConstrainedOctetString(1, 8, true),
+			value: []byte{},
+			want:  result{data: []byte{0x80, 0x00}},
+		},
+		{
+			name:  `Constrained_Extension_Unaligned`,
+			octet: NewExtensibleConstrainedOctetString(1, 8, false),
+			value: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09},
+			want:  result{data: []byte{0x84, 0x80, 0x81, 0x01, 0x82, 0x02, 0x83, 0x03, 0x84, 0x04, 0x80}, shift: 1},
+		},
+		{
+			name:  `Fixed_Root`,
+			octet: NewExtensibleFixedOctetString(3, true),
+			value: []byte{0x01, 0x02, 0x03},
+			want:  result{data: []byte{0x00, 0x01, 0x02, 0x03}},
+		},
+		{
+			name:  `Fixed_Extension`,
+			octet: NewExtensibleFixedOctetString(3, true),
+			value: []byte{0x01, 0x02, 0x03, 0x04},
+			want:  result{data: []byte{0x80, 0x04, 0x01, 0x02, 0x03, 0x04}},
+		},
+	} {
+		var decoded *[]byte
+		switch o := test.octet.(type) {
+		case *FixedOctetString:
+			o.Value = test.value
+			decoded = &o.Value
+		case *ConstrainedOctetString:
+			o.Value = test.value
+			decoded = &o.Value
+		}
+		res := result{}
+		res.data, res.shift, res.err = test.octet.Encode(nil, 0)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+		*decoded = nil
+		out, shift, err := test.octet.Decode(res.data, 0)
+		if err != nil || len(*decoded) != len(test.value) || len(test.value) != 0 && !reflect.DeepEqual(test.value, *decoded) {
+			t.Logf("%s decoding is not expected \n want %x, \n got  %x (%v)", test.name, test.value, *decoded, err)
+			t.Fail()
+		}
+		if shift != res.shift || shift == 0 && len(out) != 0 || shift != 0 && len(out) != 1 {
+			t.Errorf("%s rest data %x, shift %d", test.name, out, shift)
+		}
+	}
+}
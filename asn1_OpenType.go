@@ -0,0 +1,79 @@
+package asn1_per
+
+// Open type (X.691 p.11.2): length determinant and complete encoding of inner value (padded to octet)
+
+type OpenType struct {
+	Alligned bool
+	Value    []byte // complete encoding of inner value
+}
+
+func NewOpenType(alligned bool) *OpenType {
+	return &OpenType{
+		Alligned: alligned,
+	}
+}
+
+// Decode length determinant and raw octets of inner value to Value
+func (o *OpenType) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	var value []byte
+	value, outData, outShift, err = openTypeDecode(data, shift, o.Alligned)
+	if err != nil {
+		return
+	}
+	o.Value = append(o.Value[:0], value...)
+	return
+}
+
+// Encode length determinant and Value (empty Value is encoded as one zero octet)
+func (o *OpenType) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	value := o.Value
+	if len(value) == 0 {
+		value = []byte{0x00}
+	}
+	return openTypeEncode(data, shift, value, o.Alligned)
+}
+
+// Decode from current position of BitReader
+func (o *OpenType) DecodeFrom(r *BitReader) error {
+	return r.decode(o.Decode)
+}
+
+// Encode to current position of BitWriter
+func (o *OpenType) EncodeTo(w *BitWriter) error {
+	return w.encode(o.Encode)
+}
+
+// Decode inner value from Value, only padding to octet may follow inner value
+func (o *OpenType) DecodeValue(value Codec) error {
+	if len(o.Value) == 0 {
+		return ErrorBufferToShort
+	}
+	rest, shift, err := value.Decode(o.Value, 0)
+	if err != nil {
+		return err
+	}
+	if shift != 0 {
+		rest = rest[1:]
+	}
+	// empty encoding is replaced by one zero octet
+	if len(rest) == len(o.Value) && len(rest) == 1 && rest[0] == 0x00 {
+		return nil
+	}
+	if len(rest) != 0 {
+		return ErrorIncorrectDecode
+	}
+	return nil
+}
+
+// Encode inner value to Value (complete encoding padded to octet)
+func (o *OpenType) EncodeValue(value Codec) (err error) {
+	o.Value, err = completeEncoding(value)
+	return
+}
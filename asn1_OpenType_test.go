@@ -0,0 +1,117 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOpenType(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+	for _, test := range []struct {
+		name     string
+		alligned bool
+		inner    Codec
+		input    []byte
+		shift    uint8
+		want     result
+	}{
+		{
+			name:     `Aligned`,
+			alligned: true,
+			inner:    &ConstrainedInteger{LowerBand: 0, UpperBand: 15, Alligned: true, Value: 5},
+			input:    []byte{0x80},
+			shift:    1,
+			want:     result{data: []byte{0x80, 0x01, 0x50}},
+		},
+		{
+			name:     `Unaligned`,
+			alligned: false,
+			inner:    &ConstrainedInteger{LowerBand: 0, UpperBand: 15, Alligned: false, Value: 5},
+			input:    []byte{0x80},
+			shift:    1,
+			want:     result{data: []byte{0x80, 0xa8, 0x00}, shift: 1},
+		},
+		{
+			name:     `Empty_Inner_Encoding`,
+			alligned: true,
+			inner:    NewNull(),
+			want:     result{data: []byte{0x01, 0x00}},
+		},
+		{
+			name:     `Octet_String`,
+			alligned: true,
+			inner:    &FixedOctetString{Size: 3, Alligned: true, Value: []byte{0x01, 0x02, 0x03}},
+			want:     result{data: []byte{0x03, 0x01, 0x02, 0x03}},
+		},
+	} {
+		o := NewOpenType(test.alligned)
+		if err := o.EncodeValue(test.inner); err != nil {
+			t.Fatalf("%s error encode inner value %v", test.name, err)
+		}
+		res := result{}
+		res.data, res.shift, res.err = o.Encode(test.input, test.shift)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+
+		d := NewOpenType(test.alligned)
+		out, shift, err := d.Decode(res.data, test.shift)
+		if err != nil || !reflect.DeepEqual(o.Value, d.Value) || shift != res.shift || shift == 0 && len(out) != 0 {
+			t.Errorf("%s decoding is not expected %x (%v)", test.name, d.Value, err)
+		}
+		// lazy decoding of inner value
+		if err = d.DecodeValue(test.inner); err != nil {
+			t.Errorf("%s error decode inner value %v", test.name, err)
+		}
+	}
+
+	// inner value must be decoded from raw octets
+	o := NewOpenType(true)
+	if _, _, err := o.Decode([]byte{0x01, 0x50}, 0); err != nil {
+		t.Fatalf("error decode open type %v", err)
+	}
+	integer := NewConstrainedInteger(0, 15, true)
+	if err := o.DecodeValue(integer); err != nil || integer.Value != 5 {
+		t.Errorf("Inner_Value result is not expected \n want %v, \n got  %v (%v)", 5, integer.Value, err)
+	}
+
+	// only padding may follow inner value
+	o.Value = []byte{0x50, 0x01}
+	if err := o.DecodeValue(integer); err != ErrorIncorrectDecode {
+		t.Errorf("Rest_Data result is not expected \n want %v, \n got  %v", ErrorIncorrectDecode, err)
+	}
+
+	// fragmented open type
+	o.Value = make([]byte, 20000)
+	o.Value[19999] = 0xaa
+	data, _, err := o.Encode(nil, 0)
+	if err != nil || data[0] != 0xc1 || data[16385] != 0x8e || data[16386] != 0x20 {
+		t.Errorf("Fragmented result is not expected %x (%v)", data[:1], err)
+	}
+	d := NewOpenType(true)
+	if _, _, err = d.Decode(data, 0); err != nil || !reflect.DeepEqual(o.Value, d.Value) {
+		t.Errorf("Fragmented decoding is not expected (%v)", err)
+	}
+
+	// fragmented open type from bit shift in the UNALIGNED variant, next field shares the last octet
+	u := NewOpenType(false)
+	u.Value = o.Value
+	data, shift, err := u.Encode([]byte{0xe0}, 3)
+	if err != nil || shift != 3 {
+		t.Fatalf("Unaligned_Fragmented error encode open type %d (%v)", shift, err)
+	}
+	data, _ = putBits(data, shift, 0x1f, 5)
+	d = NewOpenType(false)
+	out, shift, err := d.Decode(data, 3)
+	if err != nil || shift != 3 || !reflect.DeepEqual([]byte{0x5f}, out) || !reflect.DeepEqual(o.Value, d.Value) {
+		t.Errorf("Unaligned_Fragmented decoding is not expected %x, %d (%v)", out, shift, err)
+	}
+	if _, _, err = d.Decode(data[:len(data)-2], 3); err != ErrorBufferToShort {
+		t.Errorf("Unaligned_Truncated result is not expected \n want %v, \n got  %v", ErrorBufferToShort, err)
+	}
+}
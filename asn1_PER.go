@@ -7,12 +7,29 @@ import (
 // As Recommendation ITU-T X.691
 
 var (
-	ErrorBufferToShort   = errors.New("ASN.1 to short input buffer")
-	ErrorIncorrectLength = errors.New("ASN.1 incorrect length")
-	ErrorBigLength       = errors.New("ASN.1 length too big")
-	ErrorIncorrectDecode = errors.New("ASN.1 format decode incorrect")
-	ErrorShiftIncorrect  = errors.New("ASN.1 incorrect bitShiftValue")
-	ErrorInputParameters = errors.New("ASN.1 incorrect input parameters")
+	ErrorBufferToShort       = errors.New("ASN.1 to short input buffer")
+	ErrorIncorrectLength     = errors.New("ASN.1 incorrect length")
+	ErrorBigLength           = errors.New("ASN.1 length too big")
+	ErrorIncorrectDecode     = errors.New("ASN.1 format decode incorrect")
+	ErrorShiftIncorrect      = errors.New("ASN.1 incorrect bitShiftValue")
+	ErrorInputParameters     = errors.New("ASN.1 incorrect input parameters")
+	ErrorConstraintViolation = errors.New("ASN.1 value is out of constraint")
 )
 
-const K16 = 16383
+// Fragment unit of length determinant (X.691 p.11.9.3.8)
+const K16 = 16384
+
+// Unit of length determinant
+type LengthUnit int
+
+const (
+	UnitOctets     LengthUnit = iota // OCTET STRING, open type
+	UnitBits                         // BIT STRING
+	UnitComponents                   // SEQUENCE OF, SET OF
+)
+
+// Common interface of all types (Decode from and Encode to (data, shift))
+type Codec interface {
+	Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error)
+	Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error)
+}
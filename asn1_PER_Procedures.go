@@ -1,282 +1,548 @@
 package asn1_per
 
 import (
-	"encoding/binary"
 	"math"
 	"math/big"
+	"math/bits"
 )
 
 //11.5 Encoding of a constrained whole number
 
 func constrainedWholeNumber(data []byte, shift uint8, rang int, aligned bool) (result int, outData []byte, outShift uint8, err error) {
-	if len(data) == 0 {
-		err = ErrorBufferToShort
+	if rang < 1 {
+		err = ErrorInputParameters
 		return
 	}
-	if !aligned {
-		return unalignedConstrainedWholeNumber(data, shift, rang)
+	var value uint64
+	value, outData, outShift, err = constrainedWholeNumber64(data, shift, uint64(rang-1), aligned)
+	result = int(value)
+	return
+}
+
+// Constrained whole number with range span + 1 (span = ub - lb), exact for full 64-bit ranges
+func constrainedWholeNumber64(data []byte, shift uint8, span uint64, aligned bool) (result uint64, outData []byte, outShift uint8, err error) {
+	// UNALIGNED variant and the bit-field case
+	if !aligned || span < 255 {
+		return getBits(data, shift, bits.Len64(span))
 	}
-	if rang > 255 {
+	// the indefinite length case
+	if span > 65535 {
+		var length uint64
+		// X.691 11.5.7.4: length (1..octets of span) is a bit-field, value is octet-aligned
+		length, data, shift, err = getBits(data, shift, lengthSizeCalculate(span))
+		if err != nil {
+			return
+		}
+		if int(length) >= octetsNeeded(span) {
+			err = ErrorIncorrectDecode
+			return
+		}
 		if shift != 0 {
-			outShift = 0
 			data = data[1:]
 		}
+		return getBits(data, 0, (int(length)+1)*8)
 	}
-	// the bit-field case
-	if rang < 256 {
-		result, outData, outShift, err = bitFieldCase(data, shift, rang)
-		return
+	if shift != 0 {
+		data = data[1:]
 	}
 	// the one-octet case
-	if rang == 256 {
-		result, outData, err = oneOctetCase(data)
-		return
+	if span == 255 {
+		return getBits(data, 0, 8)
 	}
 	// the two-octet case
-	if 256 < rang && rang < 65536 {
-		result, outData, err = twoOctetCase(data)
-		return
-	}
-	// the indefinite length case
-	result, outData, err = indefiniteLengthCase(data)
-	return
+	return getBits(data, 0, 16)
 }
 
-// UNALIGNED variant
+// Encode value (0..rang-1) as constrained whole number (inverse of constrainedWholeNumber)
 
-func unalignedConstrainedWholeNumber(data []byte, shift uint8, rang int) (result int, outData []byte, outShift uint8, err error) {
-	bitSize := sizeInBits(rang)
-	resSize := bitSize / 8
-	if bitSize%8 != 0 {
-		resSize += 1
+func encodeConstrainedWholeNumber(data []byte, shift uint8, value int, rang int, aligned bool) (outData []byte, outShift uint8, err error) {
+	if value < 0 || value >= rang {
+		err = ErrorInputParameters
+		return
 	}
-	byteSize := resSize
-	if (shift+uint8(bitSize%8) > 8) || (shift != 0 && bitSize%8 == 0) {
-		byteSize += 1
+	return encodeConstrainedWholeNumber64(data, shift, uint64(value), uint64(rang-1), aligned)
+}
+
+func encodeConstrainedWholeNumber64(data []byte, shift uint8, value uint64, span uint64, aligned bool) (outData []byte, outShift uint8, err error) {
+	if value > span {
+		err = ErrorInputParameters
+		return
 	}
-	if len(data) < byteSize {
-		err = ErrorBufferToShort
+	// UNALIGNED variant and the bit-field case
+	if !aligned || span < 255 {
+		outData, outShift = putBits(data, shift, value, bits.Len64(span))
 		return
 	}
-	if resSize > 8 {
-		// To big Value
-		err = ErrorIncorrectDecode
+	// the indefinite length case (X.691 11.5.7.4): length (1..octets of span) is a bit-field,
+	// value is octet-aligned in the minimum number of octets
+	if span > 65535 {
+		size := octetsNeeded(value)
+		outData, _ = putBits(data, shift, uint64(size-1), lengthSizeCalculate(span))
+		outData, outShift = putBits(outData, 0, value, size*8)
 		return
 	}
-	bufRes := make([]byte, 0, resSize)
-	padding := bitStringDecode(data, shift, uint8(bitSize%8), byteSize, &bufRes)
-
-	result = int(big.NewInt(0).SetBytes(bufRes).Uint64())
-	if padding == 0 {
-		outData = data[byteSize:]
-		outShift = 0
-	} else {
-		outData = data[byteSize-1:]
-		outShift = 8 - padding
+	// all other cases are octet-aligned, padding bits of the last octet are already zero
+	shift = 0
+	// the one-octet case
+	if span == 255 {
+		outData, outShift = putBits(data, shift, value, 8)
+		return
 	}
+	// the two-octet case
+	outData, outShift = putBits(data, shift, value, 16)
 	return
 }
 
-// the bit-field case
-func bitFieldCase(data []byte, shift uint8, rang int) (result int, outData []byte, outShift uint8, err error) {
-	var padding uint8
-	sizeLen := uint8(sizeInBits(rang))
-	if (shift + sizeLen) < 8 {
-		padding = 8 - (shift + sizeLen)
-		result = int((data[0] >> padding) & (0xff >> (8 - sizeLen)))
-		outData = data
-		outShift = shift + sizeLen
-	} else {
-		if len(data) < 2 {
-			err = ErrorBufferToShort
-			return
+// return number of bits nedded for length (1..number of octets for span) coding
+func lengthSizeCalculate(span uint64) int {
+	return bits.Len(uint(octetsNeeded(span) - 1))
+}
+
+// Append size low bits of value to data, shift - number of bits already used in last octet of data.
+// Return data and number of used bits in the last octet (0 - if data end on the corner of octet)
+func putBits(data []byte, shift uint8, value uint64, size int) (outData []byte, outShift uint8) {
+	for i := size - 1; i >= 0; i-- {
+		if shift == 0 {
+			data = append(data, 0)
+		}
+		if (value>>uint(i))&1 == 1 {
+			data[len(data)-1] |= 0x80 >> shift
 		}
-		result = int((data[0]<<shift | data[1]>>(8-shift)) >> (8 - sizeLen))
-		outData = data[1:]
-		outShift = shift + sizeLen - 8
+		shift = (shift + 1) % 8
 	}
-	return
+	return data, shift
 }
 
-// the one-octet case
-func oneOctetCase(data []byte) (result int, outData []byte, err error) {
-	if len(data) == 0 {
-		err = ErrorBufferToShort
+// 11.6 Encoding of a normally small non-negative whole number (CHOICE and ENUMERATED extension index),
+// 0..63 is encoded as bit 0 and 6-bit value, bigger values as bit 1 and semi-constrained whole number
+
+func DecodeNormallySmallNumber(data []byte, shift uint8, aligned bool) (result int, outData []byte, outShift uint8, err error) {
+	var bit, value uint64
+	bit, data, shift, err = getBits(data, shift, 1)
+	if err != nil {
 		return
 	}
-	result = int(data[0])
-	outData = data[1:]
+	if bit == 0 {
+		value, outData, outShift, err = getBits(data, shift, 6)
+		result = int(value)
+		return
+	}
+	// semi-constrained whole number with lb = 0
+	value, outData, outShift, err = semiConstrainedWholeNumber(data, shift, aligned)
+	if err != nil {
+		return
+	}
+	if value > math.MaxInt {
+		err = ErrorIncorrectDecode
+		return
+	}
+	result = int(value)
 	return
 }
 
-// the two-octet case
-func twoOctetCase(data []byte) (result int, outData []byte, err error) {
-	if len(data) < 2 {
-		err = ErrorBufferToShort
+func EncodeNormallySmallNumber(data []byte, shift uint8, value int, aligned bool) (outData []byte, outShift uint8, err error) {
+	if value < 0 {
+		err = ErrorInputParameters
 		return
 	}
-	result = int(binary.BigEndian.Uint16(data[:2]))
-	outData = data[2:]
-	return
+	if value <= 63 {
+		outData, outShift = putBits(data, shift, uint64(value), 7)
+		return
+	}
+	data, shift = putBits(data, shift, 1, 1)
+	return encodeSemiConstrainedWholeNumber(data, shift, uint64(value), aligned)
 }
 
-// the indefinite length case
+// 11.7 Encoding of a semi-constrained whole number (non-negative-binary-integer with length determinant),
+// in aligned variant length and value are octet-aligned
 
-func indefiniteLengthCase(data []byte) (result int, outData []byte, err error) {
-	if len(data) < 2 {
-		err = ErrorBufferToShort
+func semiConstrainedWholeNumber(data []byte, shift uint8, aligned bool) (result uint64, outData []byte, outShift uint8, err error) {
+	var octets []byte
+	octets, outData, outShift, err = lengthOctetsDecode(data, shift, aligned)
+	if err != nil {
 		return
 	}
-	if data[0]>>7 == 1 {
-		// Length must be less than 128 bits (16 byte). It's too big.
-		err = ErrorIncorrectDecode
+	result = big.NewInt(0).SetBytes(octets).Uint64()
+	return
+}
+
+func encodeSemiConstrainedWholeNumber(data []byte, shift uint8, value uint64, aligned bool) (outData []byte, outShift uint8, err error) {
+	octets, _ := putBits(nil, 0, value, octetsNeeded(value)*8)
+	return openTypeEncode(data, shift, octets, aligned)
+}
+
+// 11.8 Encoding of an unconstrained whole number (2's-complement-binary-integer with length determinant),
+// in aligned variant length and value are octet-aligned
+
+func unconstrainedWholeNumber(data []byte, shift uint8, aligned bool) (result int64, outData []byte, outShift uint8, err error) {
+	var octets []byte
+	octets, outData, outShift, err = lengthOctetsDecode(data, shift, aligned)
+	if err != nil {
 		return
 	}
-	length := data[0]
-	data = data[1:]
-	if length > 8 {
+	result = parseInt64(octets)
+	return
+}
+
+func encodeUnconstrainedWholeNumber(data []byte, shift uint8, value int64, aligned bool) (outData []byte, outShift uint8, err error) {
+	// minimum number of octets with sign bit
+	size := (bits.Len64(uint64(value^(value>>63))) + 8) / 8
+	octets, _ := putBits(nil, 0, uint64(value), size*8)
+	return openTypeEncode(data, shift, octets, aligned)
+}
+
+// Decode length determinant and 1..8 octets of integer value
+func lengthOctetsDecode(data []byte, shift uint8, aligned bool) (octets []byte, outData []byte, outShift uint8, err error) {
+	octets, outData, outShift, err = lengthBigOctetsDecode(data, shift, aligned)
+	if err == nil && len(octets) > 8 {
 		// To big Value
 		err = ErrorIncorrectDecode
+	}
+	return
+}
+
+// Decode length determinant and octets of integer value (any number of octets)
+func lengthBigOctetsDecode(data []byte, shift uint8, aligned bool) (octets []byte, outData []byte, outShift uint8, err error) {
+	octets, outData, outShift, err = openTypeDecode(data, shift, aligned)
+	if err != nil {
 		return
 	}
-	if len(data) < int(length) {
+	if len(octets) == 0 {
+		err = ErrorIncorrectDecode
+	}
+	return
+}
+
+// Read size bits (size <= 64) from data as non-negative-binary-integer
+func getBits(data []byte, shift uint8, size int) (value uint64, outData []byte, outShift uint8, err error) {
+	last := int(shift) + size
+	if len(data)*8 < last {
 		err = ErrorBufferToShort
 		return
 	}
-	result = int(big.NewInt(0).SetBytes(data[:length]).Uint64())
-	outData = data[length:]
+	for i := int(shift); i < last; i++ {
+		value = value<<1 | uint64((data[i/8]>>(7-i%8))&1)
+	}
+	outData = data[last/8:]
+	outShift = uint8(last % 8)
 	return
 }
 
-func sizeInBits(rang int) int {
-	x := math.Log2(float64(rang))
-	return int(math.Ceil(x))
+// Check input parameters of encode functions: if shift is not 0, data must include last not full octet
+func encodeShiftCheck(data []byte, shift uint8) error {
+	if shift > 7 || (shift != 0 && len(data) == 0) {
+		return ErrorShiftIncorrect
+	}
+	return nil
+}
+
+// Number of octets needed for non-negative-binary-integer coding (minimum 1 octet)
+func octetsNeeded(value uint64) int {
+	size := (bits.Len64(value) + 7) / 8
+	if size == 0 {
+		size = 1
+	}
+	return size
 }
 
 //11.9 General rules for encoding a length determinant
 
+// Decode octet-aligned length determinant and payload octets (fragmented payload is reassembled).
+// fullLength - number of octets of length determinants and payload in data
 func DecodeLengthDeterminant(data []byte) (fullLength int, payload []byte, err error) {
-	if len(data) < 2 {
-		err = ErrorBufferToShort
-		return
-	}
 	var length int
-	if data[0]>>7 == 0 {
-		length = int(data[0])
-		if len(data)-1 < int(length) || length == 0 {
-			err = ErrorBufferToShort
-			return
+	rest := data
+	for {
+		length, rest, _, err = lengthDeterminantDecode(rest, 0, true)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(rest) < length {
+			return 0, nil, ErrorBufferToShort
+		}
+		payload = append(payload, rest[:length]...)
+		rest = rest[length:]
+		if length < K16 {
+			break
 		}
-		fullLength = length + 1
-		payload = data[1 : 1+length]
-		return
 	}
-	if data[0]>>6 == 2 {
-		length = int(binary.BigEndian.Uint16([]byte{data[0] & 0b0011_1111, data[1]}))
-		if len(data)-2 < int(length) || length == 0 {
-			err = ErrorBufferToShort
+	if payload == nil {
+		payload = []byte{}
+	}
+	return len(data) - len(rest), payload, nil
+}
+
+// Encode octet-aligned length determinant and payload (inverse of DecodeLengthDeterminant), length - number of units.
+// 16K units and more are encoded in fragments of 16K, 32K, 48K or 64K units, if length is multiple of 16K
+// zero length octet follows the last fragment. UnitBits payload is alligned by the end of octet,
+// UnitComponents payload of 16K components and more must consist of components with equal size in octets
+func EncodeLengthDeterminant(payload []byte, length int, unit LengthUnit) (data []byte, err error) {
+	var (
+		size  int // size of unit in bits
+		first int // first bit of fragment in payload
+		shift uint8
+	)
+	switch unit {
+	case UnitOctets:
+		if length != len(payload) {
+			err = ErrorIncorrectLength
 			return
 		}
-		fullLength = length + 2
-		payload = data[2 : 2+length]
-		return
-	}
-	for {
-		if data[0]>>7 == 0 {
-			length = int(data[0])
-			if len(data)-1 < length || length == 0 {
-				fullLength = 0
-				payload = nil
-				err = ErrorBufferToShort
-				return
-			}
-			payload = append(payload, data[1:1+length]...)
-			fullLength = fullLength + 1 + length
-			break
-		} else {
-			length = int(binary.BigEndian.Uint16([]byte{data[0] & 0b0011_1111, data[1]})) * K16
-			if len(data)-2 < length || length == 0 {
-				fullLength = 0
-				payload = nil
-				err = ErrorBufferToShort
+		size = 8
+	case UnitBits:
+		if length < 0 || len(payload) != byteSizeCalculate(length) {
+			err = ErrorIncorrectLength
+			return
+		}
+		size = 1
+		first = len(payload)*8 - length
+	case UnitComponents:
+		if length < 0 || length == 0 && len(payload) != 0 {
+			err = ErrorIncorrectLength
+			return
+		}
+		if length >= K16 {
+			if len(payload)%length != 0 {
+				err = ErrorInputParameters
 				return
 			}
-			payload = append(payload, data[2:2+length]...)
-			fullLength = fullLength + 2 + length
-			if len(data[2+length:]) == 0 {
-				break
-			}
-			data = data[2+length:]
+			size = len(payload) / length * 8
+		}
+	default:
+		err = ErrorInputParameters
+		return
+	}
+	for _, fragment := range lengthFragments(length) {
+		data, shift, err = lengthDeterminantEncode(data, shift, fragment, true)
+		if err != nil {
+			return nil, err
+		}
+		bitSize := fragment * size
+		// the last fragment includes the rest of payload
+		if fragment < K16 {
+			bitSize = len(payload)*8 - first
 		}
+		data, shift = putBitRange(data, shift, payload, first, bitSize)
+		first += bitSize
+	}
+	return data, nil
+}
+
+// 11.9.3.3 Constrained length (ub < 64K), encoded as constrained whole number length - lb,
+// nothing is encoded if lb == ub
+
+func DecodeConstrainedLength(data []byte, shift uint8, lb int, ub int, aligned bool) (length int, outData []byte, outShift uint8, err error) {
+	if lb < 0 || ub < lb || ub >= 65536 {
+		err = ErrorInputParameters
+		return
+	}
+	if lb == ub {
+		return lb, data, shift, nil
+	}
+	length, outData, outShift, err = constrainedWholeNumber(data, shift, ub-lb+1, aligned)
+	if err != nil {
+		return
+	}
+	length += lb
+	if length > ub {
+		err = ErrorIncorrectLength
 	}
 	return
 }
 
-// function calculate length (return number of information elemrnts in data stream and error).
-// If need length in bits, bits = true
-func lengthDeterminanteCalculate(data []byte, bits bool) (length int, err error) {
-	if len(data) == 0 {
+func EncodeConstrainedLength(data []byte, shift uint8, length int, lb int, ub int, aligned bool) (outData []byte, outShift uint8, err error) {
+	if lb < 0 || ub < lb || ub >= 65536 {
 		err = ErrorInputParameters
 		return
 	}
-	// length size
-	var (
-		lengthType     uint8
-		fragmentLength int
-	)
-	lengthType = data[0] >> 7
-	// 0xxx_xxxx - length size = 1 octet (rest 7 bits)
-	if lengthType == 0 {
-		length = int(data[0])
-		if length == 0 {
-			err = ErrorIncorrectDecode
-		}
+	if length < lb || length > ub {
+		err = ErrorIncorrectLength
 		return
 	}
-	lengthType = data[0] >> 6
-	// 10xx_xxxx xxxx_xxxx - length size = 2 octet (rest 14 bits)
-	if lengthType == 2 {
-		length = int(binary.BigEndian.Uint16([]byte{data[0] & 0b0011_1111, data[1]}))
-		if length == 0 {
-			err = ErrorIncorrectDecode
+	if lb == ub {
+		return data, shift, nil
+	}
+	return encodeConstrainedWholeNumber(data, shift, length-lb, ub-lb+1, aligned)
+}
+
+// Encode length determinant (1 or 2 octets, or fragment header for 16K, 32K, 48K and 64K),
+// in aligned variant length is octet-aligned
+func lengthDeterminantEncode(data []byte, shift uint8, length int, aligned bool) (outData []byte, outShift uint8, err error) {
+	if aligned {
+		shift = 0
+	}
+	switch {
+	case length < 0:
+		err = ErrorIncorrectLength
+	case length < 128:
+		// 0xxx_xxxx
+		outData, outShift = putBits(data, shift, uint64(length), 8)
+	case length < K16:
+		// 10xx_xxxx xxxx_xxxx
+		outData, outShift = putBits(data, shift, uint64(0x8000|length), 16)
+	case length%K16 == 0 && length <= 4*K16:
+		// 11xx_xxxx - fragment of m * 16K units
+		outData, outShift = putBits(data, shift, uint64(0xc0|length/K16), 8)
+	default:
+		err = ErrorBigLength
+	}
+	return
+}
+
+// Split length to fragments (X.691 p.11.9.3.8): fragments of 64K, 48K, 32K or 16K units
+// and last fragment less than 16K (0 if length is multiple of 16K)
+func lengthFragments(length int) (fragments []int) {
+	for length >= K16 {
+		fragment := length / K16
+		if fragment > 4 {
+			fragment = 4
 		}
+		fragments = append(fragments, fragment*K16)
+		length -= fragment * K16
+	}
+	return append(fragments, length)
+}
+
+// Decode length determinant (1 or 2 octets, or fragment header), in aligned variant length is octet-aligned.
+// Length of fragment is 16K, 32K, 48K or 64K units and next length determinant follows fragment
+func lengthDeterminantDecode(data []byte, shift uint8, aligned bool) (length int, outData []byte, outShift uint8, err error) {
+	if aligned && shift != 0 {
+		data = data[1:]
+		shift = 0
+	}
+	var first, second uint64
+	first, data, shift, err = getBits(data, shift, 8)
+	if err != nil {
 		return
 	}
-	byteDivisor := 1
-	if bits {
-		byteDivisor = 8
+	// 0xxx_xxxx - length size = 1 octet (rest 7 bits)
+	if first>>7 == 0 {
+		return int(first), data, shift, nil
 	}
 	// 11xx_xxxx - fragmentation case
+	if first>>6 == 3 {
+		m := int(first & 0b0011_1111)
+		if m == 0 || m > 4 {
+			err = ErrorIncorrectLength
+			return
+		}
+		return m * K16, data, shift, nil
+	}
+	// 10xx_xxxx xxxx_xxxx - length size = 2 octet (rest 14 bits)
+	second, data, shift, err = getBits(data, shift, 8)
+	if err != nil {
+		return
+	}
+	return int((first&0b0011_1111)<<8 | second), data, shift, nil
+}
+
+// 11.9.3.4 Normally small length (n >= 1), used for extension bitmap length
+
+func DecodeNormallySmallLength(data []byte, shift uint8, aligned bool) (length int, outData []byte, outShift uint8, err error) {
+	var bit, value uint64
+	bit, data, shift, err = getBits(data, shift, 1)
+	if err != nil {
+		return
+	}
+	if bit == 0 {
+		value, outData, outShift, err = getBits(data, shift, 6)
+		length = int(value) + 1
+		return
+	}
+	length, outData, outShift, err = lengthDeterminantDecode(data, shift, aligned)
+	if err == nil && length >= K16 {
+		// fragmentation case is not realized
+		err = ErrorBigLength
+	}
+	return
+}
+
+func EncodeNormallySmallLength(data []byte, shift uint8, length int, aligned bool) (outData []byte, outShift uint8, err error) {
+	if length < 1 {
+		err = ErrorIncorrectLength
+		return
+	}
+	if length <= 64 {
+		outData, outShift = putBits(data, shift, uint64(length-1), 7)
+		return
+	}
+	if length >= K16 {
+		// fragmentation case is not realized
+		err = ErrorBigLength
+		return
+	}
+	data, shift = putBits(data, shift, 1, 1)
+	return lengthDeterminantEncode(data, shift, length, aligned)
+}
+
+// 11.2 Open type field: length determinant and octets (octet-aligned in the ALIGNED variant),
+// octet-aligned fields are built on DecodeLengthDeterminant, all fields on EncodeLengthDeterminant
+
+func openTypeDecode(data []byte, shift uint8, aligned bool) (value []byte, outData []byte, outShift uint8, err error) {
+	if shift != 0 && len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	if aligned || shift == 0 {
+		if shift != 0 {
+			data = data[1:]
+		}
+		var fullLength int
+		fullLength, value, err = DecodeLengthDeterminant(data)
+		if err != nil {
+			return
+		}
+		return value, data[fullLength:], 0, nil
+	}
+	// UNALIGNED variant, length determinant and octets start from bit shift
+	var length int
+	value = []byte{}
 	for {
-		lengthType = data[0] >> 7
-		if lengthType == 0 {
-			length += int(data[0])
+		length, data, shift, err = lengthDeterminantDecode(data, shift, false)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if len(data)*8 < int(shift)+length*8 {
+			return nil, nil, 0, ErrorBufferToShort
+		}
+		value = appendShiftedOctets(value, data, shift, length)
+		data = data[length:]
+		if length < K16 {
 			break
-		} else {
-			if lengthType != 3 {
-				err = ErrorIncorrectLength
-				return
-			}
-			if len(data) < 2 {
-				err = ErrorInputParameters
-				return
-			}
-			fragmentLength = int(binary.BigEndian.Uint16([]byte{data[0] & 0b0011_1111, data[1]})) * K16
-			if fragmentLength == 0 || fragmentLength > 4 {
-				err = ErrorIncorrectLength
-				return
-			}
-			fragmentLength = fragmentLength / byteDivisor
-			if len(data)-2 < fragmentLength/8 {
-				err = ErrorIncorrectLength
-				return
-			}
-			if len(data[2+fragmentLength:]) == 0 {
-				break
-			}
-			data = data[2+fragmentLength:]
-			length += fragmentLength
 		}
 	}
+	return value, data, shift, nil
+}
+
+func openTypeEncode(data []byte, shift uint8, value []byte, aligned bool) (outData []byte, outShift uint8, err error) {
+	var octets []byte
+	octets, err = EncodeLengthDeterminant(value, len(value), UnitOctets)
+	if err != nil {
+		return
+	}
+	if aligned {
+		shift = 0
+	}
+	outData, outShift = putBitString(data, shift, octets, len(octets)*8)
+	return
+}
+
+// Append length full octets of data starting from bit shift (shift != 0) to value
+func appendShiftedOctets(value []byte, data []byte, shift uint8, length int) []byte {
+	for i := 0; i < length; i++ {
+		value = append(value, data[i]<<shift|data[i+1]>>(8-shift))
+	}
+	return value
+}
+
+// Complete encoding of value (X.691 p.11.1), padded to octet, empty encoding replaced by one zero octet
+func completeEncoding(value Codec) (data []byte, err error) {
+	data, _, err = value.Encode(nil, 0)
+	if err != nil {
+		return
+	}
+	if len(data) == 0 {
+		data = []byte{0x00}
+	}
 	return
 }
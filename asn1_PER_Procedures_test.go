@@ -59,7 +59,7 @@ func TestUnalignedConstrainedWholeNumber(t *testing.T) {
 		},
 	} {
 		result := testData{}
-		result.value, result.data, result.shift, err = unalignedConstrainedWholeNumber(test.input.data, test.input.shift, test.input.value)
+		result.value, result.data, result.shift, err = constrainedWholeNumber(test.input.data, test.input.shift, test.input.value, false)
 		if err != nil {
 			t.Errorf("error decode unaligned constrained whole number")
 		}
@@ -69,3 +69,360 @@ func TestUnalignedConstrainedWholeNumber(t *testing.T) {
 		}
 	}
 }
+
+// octet-aligned length determinant with payload
+func TestDecodeLengthDeterminant(t *testing.T) {
+	type result struct {
+		fullLength int
+		payload    []byte
+		err        error
+	}
+	fragment := make([]byte, 16384)
+	for i := range fragment {
+		fragment[i] = byte(i)
+	}
+	for _, test := range []struct {
+		name  string
+		input []byte
+		want  result
+	}{
+		{
+			name:  `One_Octet`,
+			input: []byte{0x02, 0x01, 0x02, 0x03, 0x04},
+			want:  result{fullLength: 3, payload: []byte{0x01, 0x02}},
+		},
+		{
+			name:  `Zero_Length`,
+			input: []byte{0x00},
+			want:  result{fullLength: 1, payload: []byte{}},
+		},
+		{
+			name:  `Zero_Length_With_Rest`,
+			input: []byte{0x00, 0xff},
+			want:  result{fullLength: 1, payload: []byte{}},
+		},
+		{
+			name:  `Two_Octets`,
+			input: append([]byte{0x80, 0x80}, make([]byte, 128)...),
+			want:  result{fullLength: 130, payload: make([]byte, 128)},
+		},
+		{
+			name:  `Fragment_With_Empty_Last`,
+			input: append(append([]byte{0xc1}, fragment...), 0x00),
+			want:  result{fullLength: 16386, payload: fragment},
+		},
+		{
+			name:  `Fragment_With_Last`,
+			input: append(append([]byte{0xc1}, fragment...), 0x01, 0xaa),
+			want:  result{fullLength: 16387, payload: append(append([]byte{}, fragment...), 0xaa)},
+		},
+		{
+			name:  `Fragment_Without_Last`,
+			input: append([]byte{0xc1}, fragment...),
+			want:  result{err: ErrorBufferToShort},
+		},
+		{
+			name:  `Empty`,
+			input: []byte{},
+			want:  result{err: ErrorBufferToShort},
+		},
+		{
+			name:  `Short_Payload`,
+			input: []byte{0x03, 0x01},
+			want:  result{err: ErrorBufferToShort},
+		},
+		{
+			name:  `Incorrect_Fragment`,
+			input: []byte{0xc5, 0x00},
+			want:  result{err: ErrorIncorrectLength},
+		},
+	} {
+		res := result{}
+		res.fullLength, res.payload, res.err = DecodeLengthDeterminant(test.input)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+	}
+}
+
+// constrained length (ub < 64K)
+func TestConstrainedLength(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+	for _, test := range []struct {
+		name    string
+		lb      int
+		ub      int
+		aligned bool
+		length  int
+		input   []byte
+		shift   uint8
+		want    result
+	}{
+		{name: `Zero_Length`, lb: 0, ub: 255, aligned: true, length: 0, want: result{data: []byte{0x00}}},
+		{name: `Octet`, lb: 0, ub: 255, aligned: true, length: 255, input: []byte{0x80}, shift: 1, want: result{data: []byte{0x80, 0xff}}},
+		{name: `Bits`, lb: 1, ub: 8, aligned: true, length: 8, input: []byte{0x80}, shift: 1, want: result{data: []byte{0xf0}, shift: 4}},
+		{name: `Two_Octets`, lb: 0, ub: 65535, aligned: true, length: 16384, want: result{data: []byte{0x40, 0x00}}},
+		{name: `Unaligned`, lb: 0, ub: 255, aligned: false, length: 3, input: []byte{0x80}, shift: 1, want: result{data: []byte{0x81, 0x80}, shift: 1}},
+		{name: `Fixed`, lb: 4, ub: 4, aligned: true, length: 4, input: []byte{0x80}, shift: 1, want: result{data: []byte{0x80}, shift: 1}},
+		{name: `Out_Of_Range`, lb: 1, ub: 8, aligned: true, length: 9, want: result{err: ErrorIncorrectLength}},
+		{name: `Upper_Band_64K`, lb: 0, ub: 65536, aligned: true, length: 9, want: result{err: ErrorInputParameters}},
+	} {
+		res := result{}
+		res.data, res.shift, res.err = EncodeConstrainedLength(test.input, test.shift, test.length, test.lb, test.ub, test.aligned)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+		if res.err != nil {
+			continue
+		}
+		length, out, shift, err := DecodeConstrainedLength(res.data, test.shift, test.lb, test.ub, test.aligned)
+		if err != nil || length != test.length || shift != res.shift || shift == 0 && len(out) != 0 {
+			t.Errorf("%s decoding is not expected: length %d, shift %d (%v)", test.name, length, shift, err)
+		}
+	}
+
+	// offset above upper band (range 1..5 in 3 bits)
+	if _, _, _, err := DecodeConstrainedLength([]byte{0xe0}, 0, 1, 5, true); err != ErrorIncorrectLength {
+		t.Errorf("Decode_Out_Of_Range result is not expected \n want %v, \n got  %v", ErrorIncorrectLength, err)
+	}
+}
+
+// 11.9.3.4 normally small length
+func TestNormallySmallLength(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+	for _, test := range []struct {
+		name    string
+		aligned bool
+		length  int
+		want    result
+	}{
+		{name: `One`, aligned: true, length: 1, want: result{data: []byte{0x00}, shift: 7}},
+		{name: `64`, aligned: true, length: 64, want: result{data: []byte{0x7e}, shift: 7}},
+		{name: `65_Aligned`, aligned: true, length: 65, want: result{data: []byte{0x80, 0x41}}},
+		{name: `65_Unaligned`, aligned: false, length: 65, want: result{data: []byte{0xa0, 0x80}, shift: 1}},
+		{name: `Zero`, aligned: true, length: 0, want: result{err: ErrorIncorrectLength}},
+	} {
+		res := result{}
+		res.data, res.shift, res.err = EncodeNormallySmallLength(nil, 0, test.length, test.aligned)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+		if res.err != nil {
+			continue
+		}
+		length, _, shift, err := DecodeNormallySmallLength(res.data, 0, test.aligned)
+		if err != nil || length != test.length || shift != res.shift {
+			t.Errorf("%s decoding is not expected: length %d, shift %d (%v)", test.name, length, shift, err)
+		}
+	}
+}
+
+// EncodeLengthDeterminant is inverse of DecodeLengthDeterminant
+func TestEncodeLengthDeterminant(t *testing.T) {
+	for _, size := range []int{0, 1, 127, 128, 16383, 16384, 16385, 32768, 49152, 65536, 65536 + 32768, 100000} {
+		payload := make([]byte, size)
+		for i := range payload {
+			payload[i] = byte(0xa5 + 37*i)
+		}
+		data, err := EncodeLengthDeterminant(payload, size, UnitOctets)
+		if err != nil {
+			t.Fatalf("size %d: error encode %v", size, err)
+		}
+		if size%K16 == 0 && size != 0 && data[len(data)-1] != 0x00 {
+			t.Errorf("size %d: no trailing zero length", size)
+		}
+		fullLength, decoded, err := DecodeLengthDeterminant(data)
+		if err != nil || fullLength != len(data) || !reflect.DeepEqual(payload, decoded) {
+			t.Errorf("size %d: decoded payload is not expected, full length %d of %d (%v)", size, fullLength, len(data), err)
+		}
+	}
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	for _, test := range []struct {
+		name    string
+		payload []byte
+		length  int
+		unit    LengthUnit
+		want    result
+	}{
+		{
+			name:    `Octets`,
+			payload: []byte{0x01, 0x02},
+			length:  2,
+			unit:    UnitOctets,
+			want:    result{data: []byte{0x02, 0x01, 0x02}},
+		},
+		{
+			name:    `Octets_Empty`,
+			payload: nil,
+			length:  0,
+			unit:    UnitOctets,
+			want:    result{data: []byte{0x00}},
+		},
+		{
+			name:    `Bits`,
+			payload: []byte{0x05, 0xff},
+			length:  12,
+			unit:    UnitBits,
+			want:    result{data: []byte{0x0c, 0x5f, 0xf0}},
+		},
+		{
+			name:    `Components`,
+			payload: []byte{0x01, 0x02, 0x03},
+			length:  2,
+			unit:    UnitComponents,
+			want:    result{data: []byte{0x02, 0x01, 0x02, 0x03}},
+		},
+		{
+			name:    `Incorrect_Octets_Length`,
+			payload: []byte{0x01, 0x02},
+			length:  3,
+			unit:    UnitOctets,
+			want:    result{err: ErrorIncorrectLength},
+		},
+		{
+			name:    `Incorrect_Bits_Length`,
+			payload: []byte{0x01, 0x02},
+			length:  17,
+			unit:    UnitBits,
+			want:    result{err: ErrorIncorrectLength},
+		},
+		{
+			name:    `Incorrect_Unit`,
+			payload: []byte{0x01},
+			length:  1,
+			unit:    LengthUnit(3),
+			want:    result{err: ErrorInputParameters},
+		},
+	} {
+		res := result{}
+		res.data, res.err = EncodeLengthDeterminant(test.payload, test.length, test.unit)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+	}
+
+	// 16K bits + 3 bits: fragment 0xc1 of 2048 octets, then length 3
+	bits := make([]byte, 2049)
+	bits[0], bits[1], bits[2048] = 0x07, 0x80, 0x01
+	data, err := EncodeLengthDeterminant(bits, 16387, UnitBits)
+	if err != nil || len(data) != 2051 || data[0] != 0xc1 || data[1] != 0xf0 || data[2049] != 0x03 || data[2050] != 0x20 {
+		t.Errorf("Bits_Fragment result is not expected %x (%v)", data[:2], err)
+	}
+
+	// 16K components of 2 octets: fragment 0xc1 of 32K octets and trailing zero length
+	components := make([]byte, 2*K16)
+	data, err = EncodeLengthDeterminant(components, K16, UnitComponents)
+	if err != nil || len(data) != 2*K16+2 || data[0] != 0xc1 || data[len(data)-1] != 0x00 {
+		t.Errorf("Components_Fragment result is not expected (%v)", err)
+	}
+	if _, err = EncodeLengthDeterminant(components[1:], K16, UnitComponents); err != ErrorInputParameters {
+		t.Errorf("Components_Not_Equal result is not expected \n want %v, \n got  %v", ErrorInputParameters, err)
+	}
+}
+
+// 11.6 normally small non-negative whole number
+func TestNormallySmallNumber(t *testing.T) {
+	type result struct {
+		data  []byte
+		shift uint8
+		err   error
+	}
+	for _, test := range []struct {
+		name    string
+		aligned bool
+		value   int
+		input   []byte
+		shift   uint8
+		want    result
+	}{
+		{name: `Zero`, aligned: true, value: 0, want: result{data: []byte{0x00}, shift: 7}},
+		{name: `One`, aligned: false, value: 1, want: result{data: []byte{0x02}, shift: 7}},
+		{name: `63`, aligned: true, value: 63, want: result{data: []byte{0x7e}, shift: 7}},
+		{name: `63_Shift`, aligned: true, value: 63, input: []byte{0xe0}, shift: 3, want: result{data: []byte{0xef, 0xc0}, shift: 2}},
+		{name: `63_Shift_1`, aligned: false, value: 63, input: []byte{0x80}, shift: 1, want: result{data: []byte{0xbf}}},
+		{name: `64_Aligned`, aligned: true, value: 64, want: result{data: []byte{0x80, 0x01, 0x40}}},
+		{name: `64_Aligned_Shift`, aligned: true, value: 64, input: []byte{0xe0}, shift: 3, want: result{data: []byte{0xf0, 0x01, 0x40}}},
+		{name: `64_Unaligned`, aligned: false, value: 64, want: result{data: []byte{0x80, 0xa0, 0x00}, shift: 1}},
+		{name: `255_Aligned`, aligned: true, value: 255, want: result{data: []byte{0x80, 0x01, 0xff}}},
+		{name: `256_Aligned`, aligned: true, value: 256, want: result{data: []byte{0x80, 0x02, 0x01, 0x00}}},
+		{name: `256_Unaligned`, aligned: false, value: 256, want: result{data: []byte{0x81, 0x00, 0x80, 0x00}, shift: 1}},
+		{name: `65535_Aligned`, aligned: true, value: 65535, want: result{data: []byte{0x80, 0x02, 0xff, 0xff}}},
+		{name: `65536_Aligned`, aligned: true, value: 65536, want: result{data: []byte{0x80, 0x03, 0x01, 0x00, 0x00}}},
+		{name: `Negative`, aligned: true, value: -1, want: result{err: ErrorInputParameters}},
+	} {
+		res := result{}
+		res.data, res.shift, res.err = EncodeNormallySmallNumber(test.input, test.shift, test.value, test.aligned)
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+		if res.err != nil {
+			continue
+		}
+		value, out, shift, err := DecodeNormallySmallNumber(res.data, test.shift, test.aligned)
+		if err != nil || value != test.value || shift != res.shift || shift == 0 && len(out) != 0 || shift != 0 && len(out) != 1 {
+			t.Errorf("%s decoding is not expected: value %d, shift %d (%v)", test.name, value, shift, err)
+		}
+	}
+
+	// boundaries of one-bit, 6-bit and semi-constrained forms for every shift
+	for _, aligned := range []bool{true, false} {
+		for _, value := range []int{0, 1, 62, 63, 64, 65, 127, 128, 255, 256, 257, 65535, 65536, 16777215, 16777216, 1<<31 - 1, 1 << 31, 1<<62 - 1} {
+			for shift := uint8(0); shift < 8; shift++ {
+				var input []byte
+				if shift != 0 {
+					input = []byte{0xff << (8 - shift)}
+				}
+				data, outShift, err := EncodeNormallySmallNumber(input, shift, value, aligned)
+				if err != nil {
+					t.Fatalf("value %d shift %d: error encode %v", value, shift, err)
+				}
+				// 7 bits for 0..63
+				if value <= 63 && len(data)*8-int(8-outShift)%8 != int(shift)+7 {
+					t.Errorf("value %d shift %d aligned %v: %d bits are used", value, shift, aligned, len(data)*8-int(8-outShift)%8-int(shift))
+				}
+				decoded, out, decShift, err := DecodeNormallySmallNumber(data, shift, aligned)
+				if err != nil || decoded != value || decShift != outShift {
+					t.Errorf("value %d shift %d aligned %v: decoded %d (%v)", value, shift, aligned, decoded, err)
+				}
+				if outShift == 0 && len(out) != 0 || outShift != 0 && len(out) != 1 {
+					t.Errorf("value %d shift %d aligned %v: rest data %x", value, shift, aligned, out)
+				}
+			}
+		}
+	}
+
+	// truncated input
+	for _, test := range []struct {
+		name    string
+		aligned bool
+		input   []byte
+		shift   uint8
+	}{
+		{name: `Empty`, aligned: true, input: []byte{}},
+		{name: `Six_Bits`, aligned: true, input: []byte{0x00}, shift: 2},
+		{name: `No_Length`, aligned: true, input: []byte{0x80}},
+		{name: `No_Value`, aligned: true, input: []byte{0x80, 0x02, 0x01}},
+		{name: `No_Value_Unaligned`, aligned: false, input: []byte{0x80, 0x80}},
+	} {
+		if _, _, _, err := DecodeNormallySmallNumber(test.input, test.shift, test.aligned); err != ErrorBufferToShort {
+			t.Errorf("%s result is not expected \n want %v, \n got  %v", test.name, ErrorBufferToShort, err)
+		}
+	}
+}
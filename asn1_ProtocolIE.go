@@ -0,0 +1,173 @@
+package asn1_per
+
+// ProtocolIE-Field and ProtocolIE-Container (NGAP, S1AP, X2AP):
+//
+//	ProtocolIE-Field ::= SEQUENCE {
+//	    id          ProtocolIE-ID (INTEGER (0..65535)),
+//	    criticality ENUMERATED { reject, ignore, notify },
+//	    value       open type, type depends on id in object set
+//	}
+//	ProtocolIE-Container ::= SEQUENCE (SIZE (0..maxProtocolIEs)) OF ProtocolIE-Field
+
+const (
+	CriticalityReject = iota
+	CriticalityIgnore
+	CriticalityNotify
+)
+
+type ProtocolIEField struct {
+	Objects     *ObjectRegistry // object set registry for decoding of Value
+	Set         string          // name of object set
+	Alligned    bool
+	Id          int
+	Criticality int
+	Value       Codec    // decoded value, nil if id is unknown in object set
+	Raw         OpenType // raw octets of value (unknown values are encoded from Raw)
+}
+
+func NewProtocolIEField(objects *ObjectRegistry, set string, alligned bool) *ProtocolIEField {
+	return &ProtocolIEField{
+		Objects:  objects,
+		Set:      set,
+		Alligned: alligned,
+		Raw:      OpenType{Alligned: alligned},
+	}
+}
+
+// Decode id, criticality and value (value of unknown id is kept in Raw only)
+func (f *ProtocolIEField) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if len(data) == 0 {
+		err = ErrorBufferToShort
+		return
+	}
+	f.Id, data, shift, err = constrainedWholeNumber(data, shift, 65536, f.Alligned)
+	if err != nil {
+		return
+	}
+	f.Criticality, data, shift, err = constrainedWholeNumber(data, shift, 3, f.Alligned)
+	if err != nil {
+		return
+	}
+	if f.Criticality > CriticalityNotify {
+		err = ErrorIncorrectDecode
+		return
+	}
+	f.Raw.Alligned = f.Alligned
+	outData, outShift, err = f.Raw.Decode(data, shift)
+	if err != nil {
+		return
+	}
+	f.Value = nil
+	newValue, ok := f.Objects.Lookup(f.Set, f.Id)
+	if !ok {
+		return
+	}
+	value := newValue()
+	if err = f.Raw.DecodeValue(value); err != nil {
+		return
+	}
+	f.Value = value
+	return
+}
+
+// Encode id, criticality and Value (Raw if Value is nil)
+func (f *ProtocolIEField) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if f.Criticality < CriticalityReject || f.Criticality > CriticalityNotify {
+		err = ErrorInputParameters
+		return
+	}
+	if f.Value != nil {
+		if err = f.Raw.EncodeValue(f.Value); err != nil {
+			return
+		}
+	}
+	data, shift, err = encodeConstrainedWholeNumber(data, shift, f.Id, 65536, f.Alligned)
+	if err != nil {
+		return
+	}
+	data, shift, err = encodeConstrainedWholeNumber(data, shift, f.Criticality, 3, f.Alligned)
+	if err != nil {
+		return
+	}
+	f.Raw.Alligned = f.Alligned
+	return f.Raw.Encode(data, shift)
+}
+
+// Decode from current position of BitReader
+func (f *ProtocolIEField) DecodeFrom(r *BitReader) error {
+	return r.decode(f.Decode)
+}
+
+// Encode to current position of BitWriter
+func (f *ProtocolIEField) EncodeTo(w *BitWriter) error {
+	return w.encode(f.Encode)
+}
+
+type ProtocolIEContainer struct {
+	Objects   *ObjectRegistry // object set registry for decoding of values
+	Set       string          // name of object set
+	UpperBand int             // maxProtocolIEs
+	Alligned  bool
+	Value     []*ProtocolIEField
+}
+
+func NewProtocolIEContainer(objects *ObjectRegistry, set string, ub int, alligned bool) *ProtocolIEContainer {
+	return &ProtocolIEContainer{
+		Objects:   objects,
+		Set:       set,
+		UpperBand: ub,
+		Alligned:  alligned,
+	}
+}
+
+func (c *ProtocolIEContainer) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	fields := c.sequenceOf()
+	outData, outShift, err = fields.Decode(data, shift)
+	if err != nil {
+		return
+	}
+	c.Value = c.Value[:0]
+	for _, field := range fields.Value {
+		c.Value = append(c.Value, field.(*ProtocolIEField))
+	}
+	return
+}
+
+func (c *ProtocolIEContainer) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	fields := c.sequenceOf()
+	for _, field := range c.Value {
+		field.Alligned = c.Alligned
+		fields.Value = append(fields.Value, field)
+	}
+	return fields.Encode(data, shift)
+}
+
+// Decode from current position of BitReader
+func (c *ProtocolIEContainer) DecodeFrom(r *BitReader) error {
+	return r.decode(c.Decode)
+}
+
+// Encode to current position of BitWriter
+func (c *ProtocolIEContainer) EncodeTo(w *BitWriter) error {
+	return w.encode(c.Encode)
+}
+
+// Return first field with id, nil if there is no such field
+func (c *ProtocolIEContainer) Field(id int) *ProtocolIEField {
+	for _, field := range c.Value {
+		if field.Id == id {
+			return field
+		}
+	}
+	return nil
+}
+
+func (c *ProtocolIEContainer) sequenceOf() *SequenceOf {
+	newField := func() Codec {
+		return NewProtocolIEField(c.Objects, c.Set, c.Alligned)
+	}
+	return NewConstrainedSequenceOf(newField, 0, c.UpperBand, c.Alligned)
+}
@@ -0,0 +1,88 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+// S1AP InitialUEMessage-like container: eNB-UE-S1AP-ID (8), NAS-PDU (26) and unknown id 67
+func TestProtocolIEContainer(t *testing.T) {
+	objects := NewObjectRegistry()
+	objects.Register(`InitialUEMessage-IEs`, 8, func() Codec { return NewConstrainedInteger(0, 16777215, true) })
+	objects.Register(`InitialUEMessage-IEs`, 26, func() Codec { return NewUnconstrainedOctetString(true) })
+
+	encoded := []byte{
+		0x00, 0x03, // 3 fields
+		0x00, 0x08, 0x00, 0x02, 0x00, 0x03, // id 8, reject, value 3
+		0x00, 0x1a, 0x00, 0x04, 0x03, 0x7e, 0x00, 0x41, // id 26, reject, NAS-PDU
+		0x00, 0x43, 0x40, 0x02, 0x12, 0x34, // id 67, ignore, unknown value
+	}
+
+	c := NewProtocolIEContainer(objects, `InitialUEMessage-IEs`, 65535, true)
+	out, shift, err := c.Decode(encoded, 0)
+	if err != nil {
+		t.Fatalf("error decode container %v", err)
+	}
+	if len(out) != 0 || shift != 0 || len(c.Value) != 3 {
+		t.Fatalf("container result is not expected: %d fields, rest %x", len(c.Value), out)
+	}
+	if id, ok := c.Field(8).Value.(*ConstrainedInteger); !ok || id.Value != 3 {
+		t.Errorf("id 8 value is not expected %v", c.Field(8).Value)
+	}
+	if nas, ok := c.Field(26).Value.(*UnconstrainedOctetString); !ok || !reflect.DeepEqual([]byte{0x7e, 0x00, 0x41}, nas.Value) {
+		t.Errorf("id 26 value is not expected %v", c.Field(26).Value)
+	}
+	unknown := c.Field(67)
+	if unknown.Value != nil || unknown.Criticality != CriticalityIgnore || !reflect.DeepEqual([]byte{0x12, 0x34}, unknown.Raw.Value) {
+		t.Errorf("id 67 value is not expected %v %x", unknown.Value, unknown.Raw.Value)
+	}
+	if c.Field(1) != nil {
+		t.Errorf("absent id 1 is found")
+	}
+
+	// decoded container is encoded back without changes
+	data, shift, err := c.Encode(nil, 0)
+	if err != nil || shift != 0 || !reflect.DeepEqual(encoded, data) {
+		t.Errorf("result is not expected \n want %x, \n got  %x (%v)", encoded, data, err)
+	}
+
+	// encode container from values
+	c = NewProtocolIEContainer(objects, `InitialUEMessage-IEs`, 65535, true)
+	c.Value = []*ProtocolIEField{
+		{Id: 8, Criticality: CriticalityReject, Value: &ConstrainedInteger{LowerBand: 0, UpperBand: 16777215, Alligned: true, Value: 3}},
+		{Id: 26, Criticality: CriticalityReject, Value: &UnconstrainedOctetString{Alligned: true, Value: []byte{0x7e, 0x00, 0x41}}},
+		{Id: 67, Criticality: CriticalityIgnore, Raw: OpenType{Value: []byte{0x12, 0x34}}},
+	}
+	data, _, err = c.Encode(nil, 0)
+	if err != nil || !reflect.DeepEqual(encoded, data) {
+		t.Errorf("result is not expected \n want %x, \n got  %x (%v)", encoded, data, err)
+	}
+}
+
+func TestProtocolIEField(t *testing.T) {
+	objects := NewObjectRegistry()
+	objects.Register(`IEs`, 0, func() Codec { return NewConstrainedInteger(0, 15, false) })
+
+	// unaligned field: id 16 bits, criticality 2 bits, not alligned open type
+	f := NewProtocolIEField(objects, `IEs`, false)
+	f.Criticality = CriticalityNotify
+	f.Value = &ConstrainedInteger{LowerBand: 0, UpperBand: 15, Value: 5}
+	data, shift, err := f.Encode(nil, 0)
+	want := []byte{0x00, 0x00, 0x80, 0x54, 0x00}
+	if err != nil || shift != 2 || !reflect.DeepEqual(want, data) {
+		t.Errorf("result is not expected \n want %x, \n got  %x shift %d (%v)", want, data, shift, err)
+	}
+	d := NewProtocolIEField(objects, `IEs`, false)
+	if _, _, err = d.Decode(data, 0); err != nil || d.Criticality != CriticalityNotify || d.Value.(*ConstrainedInteger).Value != 5 {
+		t.Errorf("decoding is not expected %v (%v)", d.Value, err)
+	}
+
+	// criticality has 3 values
+	if _, _, err = d.Decode([]byte{0x00, 0x00, 0xc0, 0x54, 0x00}, 0); err != ErrorIncorrectDecode {
+		t.Errorf("Incorrect_Criticality result is not expected \n want %v, \n got  %v", ErrorIncorrectDecode, err)
+	}
+	// value of known id must be decoded by its codec
+	if _, _, err = d.Decode([]byte{0x00, 0x00, 0x00, 0x94, 0x00, 0x40}, 0); err != ErrorIncorrectDecode {
+		t.Errorf("Incorrect_Value result is not expected \n want %v, \n got  %v", ErrorIncorrectDecode, err)
+	}
+}
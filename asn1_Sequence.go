@@ -0,0 +1,266 @@
+package asn1_per
+
+// SEQUENCE component
+
+type SequenceComponent struct {
+	Value    Codec
+	Optional bool   // OPTIONAL or DEFAULT component (has bit in preamble)
+	Default  func() // set default value to Value (DEFAULT component)
+	Present  bool   // component is present in encoding
+}
+
+func NewComponent(value Codec) *SequenceComponent {
+	return &SequenceComponent{
+		Value:   value,
+		Present: true,
+	}
+}
+
+func NewOptionalComponent(value Codec) *SequenceComponent {
+	return &SequenceComponent{
+		Value:    value,
+		Optional: true,
+	}
+}
+
+// setDefault must set default value to value
+func NewDefaultComponent(value Codec, setDefault func()) *SequenceComponent {
+	return &SequenceComponent{
+		Value:    value,
+		Optional: true,
+		Default:  setDefault,
+	}
+}
+
+// Extension addition group [[ ]], encoded as SEQUENCE of group components.
+// Present must be set before encoding if any component of group is present
+func NewExtensionGroup(components ...*SequenceComponent) *SequenceComponent {
+	return &SequenceComponent{
+		Value:    NewSequence(components...),
+		Optional: true,
+	}
+}
+
+// SEQUENCE Type (X.691 p.19)
+
+type Sequence struct {
+	Components []*SequenceComponent
+	Extensible bool                 // SEQUENCE with extension marker "..."
+	Extensions []*SequenceComponent // extension additions (single components or groups)
+	Alligned   bool                 // format of extension additions (open types)
+}
+
+func NewSequence(components ...*SequenceComponent) *Sequence {
+	return &Sequence{
+		Components: components,
+	}
+}
+
+// SEQUENCE with extension marker, unknown extension additions are skipped during decoding
+func NewExtensibleSequence(alligned bool, components []*SequenceComponent, extensions ...*SequenceComponent) *Sequence {
+	return &Sequence{
+		Components: components,
+		Extensible: true,
+		Extensions: extensions,
+		Alligned:   alligned,
+	}
+}
+
+// Decode preamble and all present components. Absent DEFAULT components get default value
+func (s *Sequence) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	var extended uint64
+	if s.Extensible {
+		extended, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+	}
+	var preamble []byte
+	size := s.preambleSize()
+	if size > 0 {
+		if size >= 65536 {
+			err = ErrorBigLength
+			return
+		}
+		if len(data) == 0 {
+			err = ErrorBufferToShort
+			return
+		}
+		data, shift, err = fixedBitStringDecode(data, shift, size, false, &preamble)
+		if err != nil {
+			return
+		}
+	}
+	bit := 0
+	for _, c := range s.Components {
+		if c.Optional {
+			c.Present = bitIsSet(preamble, size, bit)
+			bit++
+			if !c.Present {
+				if c.Default != nil {
+					c.Default()
+				}
+				continue
+			}
+		} else {
+			c.Present = true
+		}
+		data, shift, err = c.Value.Decode(data, shift)
+		if err != nil {
+			return
+		}
+	}
+	for _, c := range s.Extensions {
+		c.Present = false
+	}
+	if extended == 1 {
+		data, shift, err = s.extensionsDecode(data, shift)
+		if err != nil {
+			return
+		}
+	}
+	for _, c := range s.Extensions {
+		if !c.Present && c.Default != nil {
+			c.Default()
+		}
+	}
+	return data, shift, nil
+}
+
+// Encode preamble and all present components (mandatory components are always encoded)
+func (s *Sequence) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	if s.preambleSize() >= 65536 {
+		err = ErrorBigLength
+		return
+	}
+	extended := false
+	for _, c := range s.Extensions {
+		extended = extended || c.Present
+	}
+	if s.Extensible {
+		data, shift = putBits(data, shift, boolToBit(extended), 1)
+	} else if extended {
+		err = ErrorInputParameters
+		return
+	}
+	for _, c := range s.Components {
+		if c.Optional {
+			data, shift = putBits(data, shift, boolToBit(c.Present), 1)
+		}
+	}
+	for _, c := range s.Components {
+		if c.Optional && !c.Present {
+			continue
+		}
+		data, shift, err = c.Value.Encode(data, shift)
+		if err != nil {
+			return
+		}
+	}
+	if extended {
+		return s.extensionsEncode(data, shift)
+	}
+	return data, shift, nil
+}
+
+// Decode extension additions bitmap and open types of present additions
+func (s *Sequence) extensionsDecode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	var (
+		size  int
+		bit   uint64
+		value []byte
+	)
+	size, data, shift, err = DecodeNormallySmallLength(data, shift, s.Alligned)
+	if err != nil {
+		return
+	}
+	present := make([]bool, size)
+	for i := range present {
+		bit, data, shift, err = getBits(data, shift, 1)
+		if err != nil {
+			return
+		}
+		present[i] = bit == 1
+	}
+	for i := range present {
+		if !present[i] {
+			continue
+		}
+		value, data, shift, err = openTypeDecode(data, shift, s.Alligned)
+		if err != nil {
+			return
+		}
+		// unknown extension addition (added in the later version of specification)
+		if i >= len(s.Extensions) {
+			continue
+		}
+		s.Extensions[i].Present = true
+		_, _, err = s.Extensions[i].Value.Decode(value, 0)
+		if err != nil {
+			return
+		}
+	}
+	return data, shift, nil
+}
+
+// Encode extension additions bitmap and present additions as open types
+func (s *Sequence) extensionsEncode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	data, shift, err = EncodeNormallySmallLength(data, shift, len(s.Extensions), s.Alligned)
+	if err != nil {
+		return
+	}
+	for _, c := range s.Extensions {
+		data, shift = putBits(data, shift, boolToBit(c.Present), 1)
+	}
+	var value []byte
+	for _, c := range s.Extensions {
+		if !c.Present {
+			continue
+		}
+		value, err = completeEncoding(c.Value)
+		if err != nil {
+			return
+		}
+		data, shift, err = openTypeEncode(data, shift, value, s.Alligned)
+		if err != nil {
+			return
+		}
+	}
+	return data, shift, nil
+}
+
+// Decode from current position of BitReader
+func (s *Sequence) DecodeFrom(r *BitReader) error {
+	return r.decode(s.Decode)
+}
+
+// Encode to current position of BitWriter
+func (s *Sequence) EncodeTo(w *BitWriter) error {
+	return w.encode(s.Encode)
+}
+
+// number of OPTIONAL and DEFAULT components
+func (s *Sequence) preambleSize() (size int) {
+	for _, c := range s.Components {
+		if c.Optional {
+			size++
+		}
+	}
+	return
+}
+
+// Check bit number i of bit string (size bits alligned by the end of octet)
+func bitIsSet(value []byte, size int, i int) bool {
+	i += len(value)*8 - size
+	return value[i/8]&(0x80>>uint(i%8)) != 0
+}
+
+func boolToBit(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
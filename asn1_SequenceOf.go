@@ -0,0 +1,142 @@
+package asn1_per
+
+// SEQUENCE OF Type (X.691 p.20)
+
+type SequenceOf struct {
+	New         func() Codec // create new element for decoding
+	Constrained bool         // SIZE (lb..ub) constraint
+	LowerBand   int
+	UpperBand   int
+	Alligned    bool
+	Value       []Codec
+}
+
+// SET OF is encoded as SEQUENCE OF
+type SetOf = SequenceOf
+
+// SEQUENCE OF without SIZE constraint
+func NewSequenceOf(newElement func() Codec, alligned bool) *SequenceOf {
+	return &SequenceOf{
+		New:      newElement,
+		Alligned: alligned,
+	}
+}
+
+// SEQUENCE (SIZE (lb..ub)) OF
+func NewConstrainedSequenceOf(newElement func() Codec, lb int, ub int, alligned bool) *SequenceOf {
+	return &SequenceOf{
+		New:         newElement,
+		Constrained: true,
+		LowerBand:   lb,
+		UpperBand:   ub,
+		Alligned:    alligned,
+	}
+}
+
+// Decode number of elements and elements to Value
+func (s *SequenceOf) Decode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if s.New == nil || (s.Constrained && (s.LowerBand < 0 || s.UpperBand < s.LowerBand)) {
+		err = ErrorInputParameters
+		return
+	}
+	s.Value = s.Value[:0]
+	// constrained whole number count
+	if s.Constrained && s.UpperBand < 65536 {
+		var count int
+		count, data, shift, err = DecodeConstrainedLength(data, shift, s.LowerBand, s.UpperBand, s.Alligned)
+		if err != nil {
+			return
+		}
+		return s.elementsDecode(data, shift, count)
+	}
+	// length determinant count with fragmentation
+	var count int
+	for {
+		count, data, shift, err = lengthDeterminantDecode(data, shift, s.Alligned)
+		if err != nil {
+			return
+		}
+		data, shift, err = s.elementsDecode(data, shift, count)
+		if err != nil {
+			return
+		}
+		if count < K16 {
+			break
+		}
+	}
+	if s.Constrained && (len(s.Value) < s.LowerBand || len(s.Value) > s.UpperBand) {
+		err = ErrorIncorrectLength
+		return
+	}
+	return data, shift, nil
+}
+
+// Encode number of elements and elements of Value
+func (s *SequenceOf) Encode(data []byte, shift uint8) (outData []byte, outShift uint8, err error) {
+	if s.Constrained && (s.LowerBand < 0 || s.UpperBand < s.LowerBand) {
+		err = ErrorInputParameters
+		return
+	}
+	if err = encodeShiftCheck(data, shift); err != nil {
+		return
+	}
+	count := len(s.Value)
+	if s.Constrained && (count < s.LowerBand || count > s.UpperBand) {
+		err = ErrorIncorrectLength
+		return
+	}
+	// constrained whole number count
+	if s.Constrained && s.UpperBand < 65536 {
+		data, shift, err = EncodeConstrainedLength(data, shift, count, s.LowerBand, s.UpperBand, s.Alligned)
+		if err != nil {
+			return
+		}
+		return s.elementsEncode(data, shift, s.Value)
+	}
+	// length determinant count with fragmentation
+	elements := s.Value
+	for _, count = range lengthFragments(count) {
+		data, shift, err = lengthDeterminantEncode(data, shift, count, s.Alligned)
+		if err != nil {
+			return
+		}
+		data, shift, err = s.elementsEncode(data, shift, elements[:count])
+		if err != nil {
+			return
+		}
+		elements = elements[count:]
+	}
+	return data, shift, nil
+}
+
+// Decode from current position of BitReader
+func (s *SequenceOf) DecodeFrom(r *BitReader) error {
+	return r.decode(s.Decode)
+}
+
+// Encode to current position of BitWriter
+func (s *SequenceOf) EncodeTo(w *BitWriter) error {
+	return w.encode(s.Encode)
+}
+
+func (s *SequenceOf) elementsDecode(data []byte, shift uint8, count int) (outData []byte, outShift uint8, err error) {
+	for i := 0; i < count; i++ {
+		element := s.New()
+		data, shift, err = element.Decode(data, shift)
+		if err != nil {
+			return
+		}
+		s.Value = append(s.Value, element)
+	}
+	return data, shift, nil
+}
+
+func (s *SequenceOf) elementsEncode(data []byte, shift uint8, elements []Codec) (outData []byte, outShift uint8, err error) {
+	for _, element := range elements {
+		data, shift, err = element.Encode(data, shift)
+		if err != nil {
+			return
+		}
+	}
+	return data, shift, nil
+}
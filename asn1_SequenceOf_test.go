@@ -0,0 +1,132 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSequenceOf(t *testing.T) {
+	type parameters struct {
+		constrained bool
+		lb          int
+		ub          int
+		bits        int // INTEGER (0..2^bits-1)
+	}
+	for _, test := range []struct {
+		name       string
+		param      parameters
+		values     []int
+		input      []byte
+		inputShift uint8
+		encoded    []byte
+		shift      uint8
+	}{
+		{
+			name:    `Constrained`,
+			param:   parameters{constrained: true, lb: 1, ub: 4, bits: 4},
+			values:  []int{1, 2, 3},
+			encoded: []byte{0x84, 0x8c},
+			shift:   6,
+		},
+		{
+			name:    `Fixed_Size`,
+			param:   parameters{constrained: true, lb: 2, ub: 2, bits: 4},
+			values:  []int{1, 2},
+			encoded: []byte{0x12},
+			shift:   0,
+		},
+		{
+			name:       `Unconstrained`,
+			param:      parameters{bits: 8},
+			values:     []int{0xaa, 0xbb},
+			input:      []byte{0xe0},
+			inputShift: 3,
+			encoded:    []byte{0xe0, 0x02, 0xaa, 0xbb},
+			shift:      0,
+		},
+		{
+			name:    `Empty`,
+			param:   parameters{bits: 8},
+			values:  []int{},
+			encoded: []byte{0x00},
+			shift:   0,
+		},
+	} {
+		newElement := func() Codec {
+			return NewConstrainedInteger(0, 1<<test.param.bits-1, true)
+		}
+		seq := NewSequenceOf(newElement, true)
+		if test.param.constrained {
+			seq = NewConstrainedSequenceOf(newElement, test.param.lb, test.param.ub, true)
+		}
+		for _, v := range test.values {
+			element := newElement().(*ConstrainedInteger)
+			element.Value = v
+			seq.Value = append(seq.Value, element)
+		}
+		encoded, shift, err := seq.Encode(test.input, test.inputShift)
+		if err != nil {
+			t.Fatalf("%s error encode sequence of %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.encoded, encoded) || shift != test.shift {
+			t.Errorf("%s encoding is not expected \n want %v, \n got  %v (shift %d)", test.name, test.encoded, encoded, shift)
+		}
+
+		seq.Value = nil
+		_, shift, err = seq.Decode(test.encoded, test.inputShift)
+		if err != nil {
+			t.Fatalf("%s error decode sequence of %v", test.name, err)
+		}
+		values := []int{}
+		for _, element := range seq.Value {
+			values = append(values, element.(*ConstrainedInteger).Value)
+		}
+		if !reflect.DeepEqual(test.values, values) || shift != test.shift {
+			t.Errorf("%s result is not expected \n want %v, \n got  %v", test.name, test.values, values)
+		}
+	}
+}
+
+// fragmentation: 16K and 64K elements fragments, last fragment can be empty
+func TestSequenceOfFragmentation(t *testing.T) {
+	newElement := func() Codec {
+		return NewConstrainedInteger(0, 255, true)
+	}
+	for _, test := range []struct {
+		count   int
+		headers map[int]byte // offset of length determinant in encoding
+	}{
+		{count: 16384, headers: map[int]byte{0: 0xc1, 16385: 0x00}},
+		{count: 16389, headers: map[int]byte{0: 0xc1, 16385: 0x05}},
+		{count: 65536 + 32768 + 100, headers: map[int]byte{0: 0xc4, 65537: 0xc2, 98306: 100}},
+	} {
+		seq := NewSequenceOf(newElement, true)
+		for i := 0; i < test.count; i++ {
+			element := newElement().(*ConstrainedInteger)
+			element.Value = i % 256
+			seq.Value = append(seq.Value, element)
+		}
+		encoded, _, err := seq.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%d error encode sequence of %v", test.count, err)
+		}
+		for offset, header := range test.headers {
+			if encoded[offset] != header {
+				t.Errorf("%d length determinant at %d: want %x, got %x", test.count, offset, header, encoded[offset])
+			}
+		}
+		decoded := NewSequenceOf(newElement, true)
+		out, _, err := decoded.Decode(encoded, 0)
+		if err != nil {
+			t.Fatalf("%d error decode sequence of %v", test.count, err)
+		}
+		if len(decoded.Value) != test.count || len(out) != 0 {
+			t.Fatalf("%d elements decoded %d, rest data %d", test.count, len(decoded.Value), len(out))
+		}
+		for i, element := range decoded.Value {
+			if element.(*ConstrainedInteger).Value != i%256 {
+				t.Fatalf("%d element %d is not expected", test.count, i)
+			}
+		}
+	}
+}
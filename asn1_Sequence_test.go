@@ -0,0 +1,182 @@
+package asn1_per
+
+import (
+	"reflect"
+	"testing"
+)
+
+//	SEQUENCE{
+//		a INTEGER (0..7) OPTIONAL,
+//		b INTEGER (0..15) DEFAULT 3,
+//		c OCTET STRING (SIZE (2))
+//	}
+func TestSequence(t *testing.T) {
+	type result struct {
+		aPresent bool
+		a        int
+		bPresent bool
+		b        int
+		c        []byte
+	}
+	for _, test := range []struct {
+		name    string
+		encoded []byte
+		shift   uint8
+		want    result
+	}{
+		{
+			name:    `Default_Absent`,
+			encoded: []byte{0xa8, 0x91, 0xa0},
+			shift:   5,
+			want: result{
+				aPresent: true,
+				a:        5,
+				b:        3,
+				c:        []byte{0x12, 0x34},
+			},
+		},
+		{
+			name:    `Optional_Absent`,
+			encoded: []byte{0x78, 0x48, 0xd0},
+			shift:   6,
+			want: result{
+				bPresent: true,
+				b:        14,
+				c:        []byte{0x12, 0x34},
+			},
+		},
+	} {
+		a := NewConstrainedInteger(0, 7, true)
+		b := NewConstrainedInteger(0, 15, true)
+		c := NewFixedOctetString(2, true)
+		seq := NewSequence(
+			NewOptionalComponent(a),
+			NewDefaultComponent(b, func() { b.Value = 3 }),
+			NewComponent(c),
+		)
+		out, shift, err := seq.Decode(test.encoded, 0)
+		if err != nil {
+			t.Fatalf("%s error decode sequence %v", test.name, err)
+		}
+		if len(out) != 1 || shift != test.shift {
+			t.Errorf("%s rest data is not expected %v (shift %d)", test.name, out, shift)
+		}
+		res := result{
+			aPresent: seq.Components[0].Present,
+			bPresent: seq.Components[1].Present,
+			b:        b.Value,
+			c:        c.Value,
+		}
+		if res.aPresent {
+			res.a = a.Value
+		}
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+
+		encoded, shift, err := seq.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode sequence %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.encoded, encoded) || shift != test.shift {
+			t.Errorf("%s encoding is not expected \n want %v, \n got  %v", test.name, test.encoded, encoded)
+		}
+	}
+}
+
+//	SEQUENCE{
+//		a INTEGER (0..7),
+//		...,
+//		b INTEGER (0..255),
+//		[[ c INTEGER (0..15),
+//		   d INTEGER (0..15) OPTIONAL ]]
+//	}
+func TestExtensibleSequence(t *testing.T) {
+	type result struct {
+		a        int
+		bPresent bool
+		b        int
+		gPresent bool
+		c        int
+		dPresent bool
+	}
+	for _, test := range []struct {
+		name    string
+		encoded []byte
+		want    result
+	}{
+		{
+			name:    `Root_Only`,
+			encoded: []byte{0x50},
+			want: result{
+				a: 5,
+			},
+		},
+		{
+			name:    `Extensions`,
+			encoded: []byte{0xd0, 0x38, 0x01, 0xc8, 0x01, 0x48},
+			want: result{
+				a:        5,
+				bPresent: true,
+				b:        200,
+				gPresent: true,
+				c:        9,
+			},
+		},
+	} {
+		a := NewConstrainedInteger(0, 7, true)
+		b := NewConstrainedInteger(0, 255, true)
+		c := NewConstrainedInteger(0, 15, true)
+		d := NewConstrainedInteger(0, 15, true)
+		seq := NewExtensibleSequence(true,
+			[]*SequenceComponent{NewComponent(a)},
+			NewOptionalComponent(b),
+			NewExtensionGroup(NewComponent(c), NewOptionalComponent(d)),
+		)
+		// trailing octet must stay in rest data
+		out, shift, err := seq.Decode(append(test.encoded, 0xab), 0)
+		if err != nil {
+			t.Fatalf("%s error decode sequence %v", test.name, err)
+		}
+		if !reflect.DeepEqual(out[len(out)-1:], []byte{0xab}) {
+			t.Errorf("%s rest data is not expected %v (shift %d)", test.name, out, shift)
+		}
+		group := seq.Extensions[1].Value.(*Sequence)
+		res := result{
+			a:        a.Value,
+			bPresent: seq.Extensions[0].Present,
+			gPresent: seq.Extensions[1].Present,
+			dPresent: group.Components[1].Present,
+		}
+		if res.bPresent {
+			res.b = b.Value
+		}
+		if res.gPresent {
+			res.c = c.Value
+		}
+		if !reflect.DeepEqual(test.want, res) {
+			t.Logf("%s result is not expected \n want %v, \n got  %v", test.name, test.want, res)
+			t.Fail()
+		}
+
+		encoded, _, err := seq.Encode(nil, 0)
+		if err != nil {
+			t.Fatalf("%s error encode sequence %v", test.name, err)
+		}
+		if !reflect.DeepEqual(test.encoded, encoded) {
+			t.Errorf("%s encoding is not expected \n want %v, \n got  %v", test.name, test.encoded, encoded)
+		}
+
+		// decoder without extension additions skips all of them
+		old := NewConstrainedInteger(0, 7, true)
+		oldSeq := NewExtensibleSequence(true, []*SequenceComponent{NewComponent(old)})
+		out, _, err = oldSeq.Decode(append(test.encoded, 0xab), 0)
+		if err != nil {
+			t.Fatalf("%s error decode sequence without extensions %v", test.name, err)
+		}
+		if old.Value != 5 || !reflect.DeepEqual(out[len(out)-1:], []byte{0xab}) {
+			t.Errorf("%s unknown extensions are not skipped: %d, %v", test.name, old.Value, out)
+		}
+	}
+}